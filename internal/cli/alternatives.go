@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexcloudstar/snappoint/internal/managers"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+	"github.com/spf13/cobra"
+)
+
+var alternativesCmd = &cobra.Command{
+	Use:   "alternatives [group]",
+	Short: "Show alternatives groups and their candidates",
+	Long: `Show the groups of the Debian or Fedora alternatives system, such as
+editor or java, with each candidate's priority and the current selection.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAlternatives,
+}
+
+var alternativesSetCmd = &cobra.Command{
+	Use:   "set <group> <candidate>",
+	Short: "Select an alternatives candidate",
+	Long: `Select the candidate an alternatives group links to, switching the group
+to manual mode. Usually needs to be run as root.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAlternativesSet,
+}
+
+var alternativesAutoCmd = &cobra.Command{
+	Use:   "auto <group>",
+	Short: "Return an alternatives group to automatic mode",
+	Long: `Return an alternatives group to automatic mode, which selects its highest
+priority candidate. Usually needs to be run as root.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAlternativesAuto,
+}
+
+func init() {
+	rootCmd.AddCommand(alternativesCmd)
+	alternativesCmd.AddCommand(alternativesSetCmd)
+	alternativesCmd.AddCommand(alternativesAutoCmd)
+}
+
+func runAlternatives(cmd *cobra.Command, args []string) error {
+	alternatives := managers.NewAlternatives(system.NewExecutor())
+
+	groups := alternatives.Groups()
+	if len(args) == 1 {
+		group := alternatives.Group(args[0])
+		if group == nil {
+			return fmt.Errorf("no alternatives group named %s", args[0])
+		}
+		groups = []*managers.AlternativeGroup{group}
+	}
+
+	if len(groups) == 0 {
+		fmt.Println("No alternatives groups found.")
+		return nil
+	}
+
+	for _, group := range groups {
+		fmt.Printf("%s (%s, %s)\n", group.Name, group.Link, group.Mode)
+		for _, candidate := range group.Candidates {
+			marker := " "
+			if candidate.Path == group.Selected {
+				marker = "*"
+			}
+			fmt.Printf("  %s %s (priority %d)\n", marker, candidate.Path, candidate.Priority)
+		}
+	}
+	return nil
+}
+
+func runAlternativesSet(cmd *cobra.Command, args []string) error {
+	alternatives := managers.NewAlternatives(system.NewExecutor())
+	if err := alternatives.Select(context.Background(), args[0], args[1]); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s now selects %s\n", args[0], args[1])
+	return nil
+}
+
+func runAlternativesAuto(cmd *cobra.Command, args []string) error {
+	alternatives := managers.NewAlternatives(system.NewExecutor())
+	if err := alternatives.Auto(context.Background(), args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is back in automatic mode\n", args[0])
+	return nil
+}
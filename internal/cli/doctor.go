@@ -17,6 +17,12 @@ var doctorCmd = &cobra.Command{
 	RunE:  runDoctor,
 }
 
+// managerCheck pairs a package manager with the name doctor displays for it
+type managerCheck struct {
+	name    string
+	manager interface{ IsAvailable(context.Context) bool }
+}
+
 func init() {
 	rootCmd.AddCommand(doctorCmd)
 }
@@ -40,13 +46,49 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Check package managers
 	fmt.Println("Package Managers:")
 
-	mgrs := []struct {
-		name    string
-		manager interface{ IsAvailable(context.Context) bool }
-	}{
-		{"Homebrew", managers.NewHomebrew(executor)},
+	// One Homebrew per install prefix, as in newPackageManagers
+	var mgrs []managerCheck
+	brews := managers.NewHomebrews(executor)
+	for _, brew := range brews {
+		name := "Homebrew"
+		if len(brews) > 1 {
+			name += " (" + brew.Prefix(ctx) + ")"
+		}
+		mgrs = append(mgrs, managerCheck{name, brew})
+	}
+
+	mgrs = append(mgrs, []managerCheck{
 		{"NPM", managers.NewNPM(executor)},
 		{"Pip", managers.NewPip(executor)},
+		{"uv", managers.NewUV(executor)},
+		{"conda", managers.NewConda(executor)},
+		{"asdf", managers.NewAsdf(executor)},
+		{"mise", managers.NewMise(executor)},
+		{"rustup", managers.NewRustup(executor)},
+		{"SDKMAN", managers.NewSDKMAN(executor)},
+		{".NET", managers.NewDotNet(executor)},
+		{"ghcup", managers.NewGhcup(executor)},
+		{"cabal", managers.NewCabal(executor)},
+		{"stack", managers.NewStack(executor)},
+		{"krew", managers.NewKrew(executor)},
+		{"gh extensions", managers.NewGhExtensions(executor)},
+		{"Docker CLI plugins", managers.NewDockerPlugins(executor)},
+		{"helm plugins", managers.NewHelmPlugins(executor)},
+		{"Deno", managers.NewDeno(executor)},
+		{"Bun", managers.NewBun(executor)},
+		{"mason.nvim", managers.NewMason(executor)},
+		{"Source builds", managers.NewSourceBuilds(executor)},
+		{"/opt bundles", managers.NewBundles(executor)},
+		{"alternatives", managers.NewAlternatives(executor)},
+		{"Portage", managers.NewPortage(executor)},
+		{"MacPorts", managers.NewMacPorts(executor)},
+		{"Perl local::lib", managers.NewPerl(executor)},
+		{"Composer global", managers.NewComposer(executor)},
+		{"Environment Modules", managers.NewModules(executor)},
+	}...)
+
+	for _, env := range managers.NewShimEnvs(executor) {
+		mgrs = append(mgrs, managerCheck{env.Name(), env})
 	}
 
 	for _, m := range mgrs {
@@ -14,6 +14,7 @@ import (
 var (
 	listOrphans   bool
 	listConflicts bool
+	listHistory   bool
 )
 
 var listCmd = &cobra.Command{
@@ -30,6 +31,7 @@ func init() {
 
 	listCmd.Flags().BoolVar(&listOrphans, "orphans", false, "Show only ghost binaries")
 	listCmd.Flags().BoolVar(&listConflicts, "conflicts", false, "Show only conflicting versions")
+	listCmd.Flags().BoolVar(&listHistory, "history", false, "Search shell history for the commands that installed ghost binaries")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -38,36 +40,14 @@ func runList(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	executor := system.NewExecutor()
 
-	// Initialize package managers
-	mgrs := []scanner.PackageManager{
-		managers.NewHomebrew(executor),
-		managers.NewNPM(executor),
-		managers.NewPip(executor),
-	}
-
-	// Create scanner
-	s := scanner.NewScanner(mgrs...)
-
 	fmt.Println("Scanning system...")
 
-	result, err := s.Scan(ctx)
-	if err != nil {
-		fmt.Printf("Warning: %v\n", err)
+	var extras []scanner.Analyzer
+	if listHistory {
+		extras = append(extras, managers.NewShellHistory())
 	}
 
-	// Scan for manual/ghost binaries
-	manualMgr := managers.NewManual(executor)
-	manualMgr.SetKnownBinaries(result.Binaries)
-
-	manualBinaries, err := manualMgr.Scan(ctx)
-	if err != nil {
-		fmt.Printf("Warning: manual scan failed: %v\n", err)
-	} else {
-		for _, binary := range manualBinaries {
-			result.AddBinary(binary)
-		}
-		result.DetectConflicts()
-	}
+	result := scanSystem(ctx, executor, "", extras...)
 
 	// Format and display results
 	formatter := output.NewTableFormatter()
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexcloudstar/snappoint/internal/managers"
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// newPackageManagers returns every package manager scanned by default.
+// The manual scanner is not included as it needs the results of the others.
+func newPackageManagers(executor system.CommandExecutor) []scanner.PackageManager {
+	var mgrs []scanner.PackageManager
+
+	// One Homebrew per install prefix, so Intel and Apple Silicon installs
+	// on the same machine are reported separately
+	for _, brew := range managers.NewHomebrews(executor) {
+		mgrs = append(mgrs, brew)
+	}
+
+	mgrs = append(mgrs,
+		managers.NewNPM(executor),
+		managers.NewPip(executor),
+		managers.NewUV(executor),
+		managers.NewConda(executor),
+		managers.NewAsdf(executor),
+		managers.NewMise(executor),
+		managers.NewRustup(executor),
+		managers.NewSDKMAN(executor),
+		managers.NewDotNet(executor),
+		managers.NewGhcup(executor),
+		managers.NewCabal(executor),
+		managers.NewStack(executor),
+		managers.NewKrew(executor),
+		managers.NewGhExtensions(executor),
+		managers.NewDockerPlugins(executor),
+		managers.NewHelmPlugins(executor),
+		managers.NewDeno(executor),
+		managers.NewBun(executor),
+		managers.NewMason(executor),
+		managers.NewSourceBuilds(executor),
+		managers.NewBundles(executor),
+		managers.NewAlternatives(executor),
+		managers.NewPortage(executor),
+		managers.NewMacPorts(executor),
+		managers.NewPerl(executor),
+		managers.NewComposer(executor),
+		managers.NewModules(executor),
+	)
+
+	for _, env := range managers.NewShimEnvs(executor) {
+		mgrs = append(mgrs, env)
+	}
+
+	return mgrs
+}
+
+// newAnalyzers returns the analyzers run over every complete scan
+func newAnalyzers(executor system.CommandExecutor) []scanner.Analyzer {
+	return []scanner.Analyzer{
+		managers.NewBundleOverlaps(),
+		managers.NewShims(executor),
+		managers.NewHomebrewDuplicates(),
+		managers.NewMasonDuplicates(),
+		managers.NewProvenance(),
+	}
+}
+
+// scanSystem scans every package manager, or only managerName when set,
+// then looks for ghost binaries and runs the analyzers, followed by any
+// opt-in extras. Scan failures are printed as warnings so partial results
+// are still shown.
+func scanSystem(ctx context.Context, executor system.CommandExecutor, managerName string, extras ...scanner.Analyzer) *scanner.ScanResult {
+	s := scanner.NewScanner(newPackageManagers(executor)...)
+
+	var result *scanner.ScanResult
+	var err error
+
+	// Scan specific manager or all
+	if managerName != "" && managerName != "manual" {
+		result, err = s.ScanSingle(ctx, managerName)
+	} else if managerName == "" {
+		result, err = s.Scan(ctx)
+	}
+
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	// If result is nil due to error or a manual-only scan, start empty
+	if result == nil {
+		result = scanner.NewScanResult()
+	}
+
+	// Now scan for manual/ghost binaries if not filtering by a specific manager
+	if managerName == "" || managerName == "manual" {
+		manualMgr := managers.NewManual(executor)
+		manualMgr.SetKnownBinaries(result.Binaries)
+
+		manualBinaries, err := manualMgr.Scan(ctx)
+		if err != nil {
+			fmt.Printf("Warning: manual scan failed: %v\n", err)
+		} else {
+			for _, binary := range manualBinaries {
+				result.AddBinary(binary)
+			}
+			// Re-detect conflicts after adding manual binaries
+			result.DetectConflicts()
+		}
+	}
+
+	result.Analyze(newAnalyzers(executor)...)
+	result.Analyze(extras...)
+
+	return result
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/alexcloudstar/snappoint/internal/managers"
+	"github.com/spf13/cobra"
+)
+
+var manifestProject string
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Manage build manifests of software installed from source",
+}
+
+var manifestAddCmd = &cobra.Command{
+	Use:   "add <install_manifest.txt>",
+	Short: "Register a build manifest",
+	Long: `Register the manifest a make install wrote, such as CMake's
+install_manifest.txt, so the binaries it installed are attributed to
+their project instead of being reported as ghosts.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runManifestAdd,
+}
+
+func init() {
+	rootCmd.AddCommand(manifestCmd)
+	manifestCmd.AddCommand(manifestAddCmd)
+
+	manifestAddCmd.Flags().StringVar(&manifestProject, "project", "", "Project name (default: the source directory's name)")
+}
+
+func runManifestAdd(cmd *cobra.Command, args []string) error {
+	project, err := managers.RegisterBuildManifest(args[0], manifestProject)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Registered %s as built from source: %s\n", args[0], project)
+	return nil
+}
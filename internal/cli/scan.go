@@ -14,6 +14,7 @@ import (
 var (
 	scanManager string
 	scanOutput  string
+	scanHistory bool
 )
 
 var scanCmd = &cobra.Command{
@@ -28,60 +29,23 @@ by any package manager.`,
 func init() {
 	rootCmd.AddCommand(scanCmd)
 
-	scanCmd.Flags().StringVar(&scanManager, "manager", "", "Filter by package manager (homebrew, npm, pip, manual)")
+	scanCmd.Flags().StringVar(&scanManager, "manager", "", "Filter by package manager (e.g. homebrew, npm, pip, mise, manual)")
 	scanCmd.Flags().StringVar(&scanOutput, "output", "table", "Output format (table, json)")
+	scanCmd.Flags().BoolVar(&scanHistory, "history", false, "Search shell history for the commands that installed ghost binaries")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	executor := system.NewExecutor()
 
-	// Initialize package managers
-	mgrs := []scanner.PackageManager{
-		managers.NewHomebrew(executor),
-		managers.NewNPM(executor),
-		managers.NewPip(executor),
-	}
-
-	// Create scanner
-	s := scanner.NewScanner(mgrs...)
-
 	fmt.Println("Scanning system for binaries...")
 
-	var result *scanner.ScanResult
-	var err error
-
-	// Scan specific manager or all
-	if scanManager != "" {
-		result, err = s.ScanSingle(ctx, scanManager)
-	} else {
-		result, err = s.Scan(ctx)
+	var extras []scanner.Analyzer
+	if scanHistory {
+		extras = append(extras, managers.NewShellHistory())
 	}
 
-	if err != nil {
-		fmt.Printf("Warning: %v\n", err)
-		// If result is nil due to error, create empty result
-		if result == nil {
-			result = scanner.NewScanResult()
-		}
-	}
-
-	// Now scan for manual/ghost binaries if not filtering by a specific manager
-	if scanManager == "" || scanManager == "manual" {
-		manualMgr := managers.NewManual(executor)
-		manualMgr.SetKnownBinaries(result.Binaries)
-
-		manualBinaries, err := manualMgr.Scan(ctx)
-		if err != nil {
-			fmt.Printf("Warning: manual scan failed: %v\n", err)
-		} else {
-			for _, binary := range manualBinaries {
-				result.AddBinary(binary)
-			}
-			// Re-detect conflicts after adding manual binaries
-			result.DetectConflicts()
-		}
-	}
+	result := scanSystem(ctx, executor, scanManager, extras...)
 
 	// Format and display results
 	formatter := output.NewTableFormatter()
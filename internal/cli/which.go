@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+	"github.com/spf13/cobra"
+)
+
+var whichCmd = &cobra.Command{
+	Use:   "which <command>",
+	Short: "Show every install of a command and what it really runs",
+	Long: `Show every install of a command found by a scan, in PATH order, with
+the one your shell runs marked. Shims, wrappers and symlinks are shown
+together with the executable they run.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWhich,
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+}
+
+func runWhich(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	executor := system.NewExecutor()
+	name := args[0]
+
+	result := scanSystem(ctx, executor, "")
+
+	var installs []*scanner.Binary
+	for _, binary := range result.Binaries {
+		if binary.Name == name {
+			installs = append(installs, binary)
+		}
+	}
+	if len(installs) == 0 {
+		return fmt.Errorf("no install of %s found", name)
+	}
+
+	// PATH order first, then installs outside PATH such as inactive versions
+	path := system.GetPATH()
+	sort.SliceStable(installs, func(i, j int) bool {
+		return pathIndex(path, installs[i].Path) < pathIndex(path, installs[j].Path)
+	})
+
+	selected := system.LookPath(name)
+	for _, binary := range installs {
+		marker := " "
+		if binary.Path == selected {
+			marker = "*"
+		}
+
+		details := binary.Manager
+		if binary.Version != "" && binary.Version != "unknown" {
+			details += " " + binary.Version
+		}
+		if binary.Kind != "" {
+			details += ", " + binary.Kind
+		}
+		if binary.Inactive {
+			details += ", inactive"
+		}
+
+		fmt.Printf("%s %s (%s)\n", marker, binary.Path, details)
+		if binary.Target != "" {
+			fmt.Printf("    → %s\n", binary.Target)
+		}
+	}
+	return nil
+}
+
+// pathIndex returns the position of a binary's directory in PATH, or
+// len(path) when it isn't on PATH
+func pathIndex(path []string, binaryPath string) int {
+	dir := filepath.Dir(binaryPath)
+	for i, entry := range path {
+		if entry != "" && filepath.Clean(entry) == dir {
+			return i
+		}
+	}
+	return len(path)
+}
@@ -0,0 +1,240 @@
+package managers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// Alternatives implements the PackageManager interface for the alternatives
+// system Debian (update-alternatives) and Fedora (alternatives) use to
+// choose between programs providing the same command, such as editor or java
+type Alternatives struct {
+	executor  system.CommandExecutor
+	adminDirs []string // Databases with one file per group
+	linkDir   string   // Directory of the links pointing at each selection
+}
+
+// AlternativeGroup is a command provided by several candidates, of which
+// the one in Selected is linked from Link
+type AlternativeGroup struct {
+	Name       string
+	Link       string // Master link, e.g. /usr/bin/editor
+	Mode       string // "auto" selects the highest priority, "manual" keeps the user's choice
+	Selected   string
+	Candidates []AlternativeCandidate
+}
+
+// AlternativeCandidate is one program that can provide a group's command
+type AlternativeCandidate struct {
+	Path     string
+	Priority int
+}
+
+// NewAlternatives creates a new alternatives package manager
+func NewAlternatives(executor system.CommandExecutor) *Alternatives {
+	return &Alternatives{
+		executor:  executor,
+		adminDirs: []string{"/var/lib/dpkg/alternatives", "/var/lib/alternatives"},
+		linkDir:   "/etc/alternatives",
+	}
+}
+
+// Name returns the name of the package manager
+func (a *Alternatives) Name() string {
+	return "alternatives"
+}
+
+// IsAvailable checks if an alternatives database exists
+func (a *Alternatives) IsAvailable(ctx context.Context) bool {
+	for _, dir := range a.adminDirs {
+		if isDir(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan reports the master link of every group that provides a command,
+// with the candidate it currently selects
+func (a *Alternatives) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	var binaries []*scanner.Binary
+	for _, group := range a.Groups() {
+		if base := filepath.Base(filepath.Dir(group.Link)); base != "bin" && base != "sbin" {
+			continue
+		}
+
+		var others []string
+		for _, candidate := range group.Candidates {
+			if candidate.Path != group.Selected {
+				others = append(others, fmt.Sprintf("%s (priority %d)", candidate.Path, candidate.Priority))
+			}
+		}
+		note := "managed alternative (" + group.Mode + ")"
+		if len(others) > 0 {
+			note += ", other candidates: " + strings.Join(others, ", ")
+		}
+
+		binaries = append(binaries, &scanner.Binary{
+			Name:    filepath.Base(group.Link),
+			Path:    group.Link,
+			Manager: a.Name(),
+			Package: group.Name,
+			Source:  group.Selected,
+			Kind:    scanner.KindSymlink,
+			Target:  group.Selected,
+			Notes:   []string{note},
+		})
+	}
+	return binaries, nil
+}
+
+// Groups reads every group in the alternatives databases, sorted by name
+func (a *Alternatives) Groups() []*AlternativeGroup {
+	seen := make(map[string]bool)
+
+	var groups []*AlternativeGroup
+	for _, dir := range a.adminDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || seen[entry.Name()] {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				continue
+			}
+			group := parseAlternatives(entry.Name(), string(data))
+			if group == nil {
+				continue
+			}
+
+			seen[entry.Name()] = true
+			group.Selected = a.selection(group)
+			groups = append(groups, group)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// Group returns the group with the given name, or nil
+func (a *Alternatives) Group(name string) *AlternativeGroup {
+	for _, group := range a.Groups() {
+		if group.Name == name {
+			return group
+		}
+	}
+	return nil
+}
+
+// Select makes candidate the group's selection, switching it to manual mode
+func (a *Alternatives) Select(ctx context.Context, name, candidate string) error {
+	group := a.Group(name)
+	if group == nil {
+		return fmt.Errorf("no alternatives group named %s", name)
+	}
+
+	var paths []string
+	for _, c := range group.Candidates {
+		if c.Path == candidate {
+			return a.run(ctx, "--set", name, candidate)
+		}
+		paths = append(paths, c.Path)
+	}
+	return fmt.Errorf("%s is not a candidate for %s, choose one of: %s", candidate, name, strings.Join(paths, ", "))
+}
+
+// Auto returns a group to automatic mode, selecting its highest priority
+// candidate
+func (a *Alternatives) Auto(ctx context.Context, name string) error {
+	if a.Group(name) == nil {
+		return fmt.Errorf("no alternatives group named %s", name)
+	}
+	return a.run(ctx, "--auto", name)
+}
+
+// run runs update-alternatives, or Fedora's alternatives where it's missing
+func (a *Alternatives) run(ctx context.Context, args ...string) error {
+	command := "update-alternatives"
+	if !a.executor.IsAvailable(ctx, command) {
+		command = "alternatives"
+	}
+	_, err := a.executor.Execute(ctx, command, args...)
+	return err
+}
+
+// selection returns the candidate a group's link currently resolves to
+func (a *Alternatives) selection(group *AlternativeGroup) string {
+	if target, err := os.Readlink(filepath.Join(a.linkDir, group.Name)); err == nil {
+		return target
+	}
+	if target, err := filepath.EvalSymlinks(group.Link); err == nil {
+		return target
+	}
+	return ""
+}
+
+// parseAlternatives reads a group's file from an alternatives database: the
+// mode, the master link and its slave links up to a blank line, then each
+// candidate's path, priority and one line per slave. Debian writes each
+// slave's name and link on separate lines, Fedora on one.
+func parseAlternatives(name, data string) *AlternativeGroup {
+	lines := strings.Split(data, "\n")
+	if len(lines) < 3 {
+		return nil
+	}
+
+	group := &AlternativeGroup{
+		Name: name,
+		Mode: strings.TrimSpace(lines[0]),
+		Link: strings.TrimSpace(lines[1]),
+	}
+
+	i, slaves := 2, 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+		if strings.Contains(strings.TrimSpace(lines[i]), " ") {
+			i++
+		} else {
+			i += 2
+		}
+		slaves++
+	}
+	i++
+
+	for i+1 < len(lines) && strings.TrimSpace(lines[i]) != "" {
+		path := strings.TrimSpace(lines[i])
+		// Fedora may prefix the path with the candidate's @family@
+		if rest, ok := strings.CutPrefix(path, "@"); ok {
+			if _, after, ok := strings.Cut(rest, "@"); ok {
+				path = after
+			}
+		}
+
+		fields := strings.Fields(lines[i+1])
+		priority := 0
+		if len(fields) > 0 {
+			priority, _ = strconv.Atoi(fields[0])
+		}
+
+		group.Candidates = append(group.Candidates, AlternativeCandidate{Path: path, Priority: priority})
+		i += 2 + slaves
+	}
+
+	if group.Link == "" || len(group.Candidates) == 0 {
+		return nil
+	}
+	return group
+}
@@ -0,0 +1,88 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseAlternatives(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		link       string
+		candidates []AlternativeCandidate
+	}{
+		{
+			name:       "debian",
+			data:       "auto\n/usr/bin/editor\neditor.1.gz\n/usr/share/man/man1/editor.1.gz\n\n/bin/nano\n40\n/usr/share/man/man1/nano.1.gz\n/usr/bin/vim.basic\n30\n\n\n",
+			link:       "/usr/bin/editor",
+			candidates: []AlternativeCandidate{{"/bin/nano", 40}, {"/usr/bin/vim.basic", 30}},
+		},
+		{
+			name:       "fedora",
+			data:       "manual\n/usr/bin/java\njre /usr/lib/jvm/jre\nkeytool /usr/bin/keytool\n\n/usr/lib/jvm/java-17/bin/java\n1700\n/usr/lib/jvm/java-17\n/usr/lib/jvm/java-17/bin/keytool\n@openjdk@/usr/lib/jvm/java-21/bin/java\n2100\n/usr/lib/jvm/java-21\n/usr/lib/jvm/java-21/bin/keytool\n",
+			link:       "/usr/bin/java",
+			candidates: []AlternativeCandidate{{"/usr/lib/jvm/java-17/bin/java", 1700}, {"/usr/lib/jvm/java-21/bin/java", 2100}},
+		},
+	}
+
+	for _, tt := range tests {
+		group := parseAlternatives(tt.name, tt.data)
+		if group == nil {
+			t.Fatalf("%s: expected a group", tt.name)
+		}
+		if group.Link != tt.link {
+			t.Errorf("%s: expected link %s, got %s", tt.name, tt.link, group.Link)
+		}
+		if len(group.Candidates) != len(tt.candidates) {
+			t.Fatalf("%s: expected %d candidates, got %+v", tt.name, len(tt.candidates), group.Candidates)
+		}
+		for i, candidate := range tt.candidates {
+			if group.Candidates[i] != candidate {
+				t.Errorf("%s: expected candidate %+v, got %+v", tt.name, candidate, group.Candidates[i])
+			}
+		}
+	}
+}
+
+func TestAlternativesScanAndSelect(t *testing.T) {
+	root := t.TempDir()
+	adminDir := filepath.Join(root, "var", "lib", "dpkg", "alternatives")
+	linkDir := filepath.Join(root, "etc", "alternatives")
+
+	writeFile(t, filepath.Join(adminDir, "editor"), "auto\n/usr/bin/editor\n\n/bin/nano\n40\n/usr/bin/vim.basic\n30\n\n", 0644)
+	writeFile(t, filepath.Join(adminDir, "x-cursor-theme"), "auto\n/usr/share/icons/default/index.theme\n\n/usr/share/icons/Adwaita/cursor.theme\n90\n\n", 0644)
+	symlink(t, "/usr/bin/vim.basic", filepath.Join(linkDir, "editor"))
+
+	executor := &fakeExecutor{
+		available: map[string]bool{"update-alternatives": true},
+		outputs:   map[string]string{"update-alternatives --set editor /bin/nano": ""},
+	}
+	alternatives := &Alternatives{executor: executor, adminDirs: []string{adminDir}, linkDir: linkDir}
+
+	binaries, err := alternatives.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binaries) != 1 {
+		t.Fatalf("Expected only the editor command, got %d binaries", len(binaries))
+	}
+	editor := binaries[0]
+	if editor.Path != "/usr/bin/editor" || editor.Source != "/usr/bin/vim.basic" || !editor.IsAlternative() {
+		t.Errorf("Expected editor selecting vim.basic, got %+v", editor)
+	}
+	if editor.Notes[0] != "managed alternative (auto), other candidates: /bin/nano (priority 40)" {
+		t.Errorf("Unexpected note %q", editor.Notes[0])
+	}
+
+	if err := alternatives.Select(context.Background(), "editor", "/bin/nano"); err != nil {
+		t.Errorf("Expected selecting nano to succeed, got %v", err)
+	}
+	if err := alternatives.Select(context.Background(), "editor", "/usr/bin/emacs"); err == nil {
+		t.Error("Expected selecting a non-candidate to fail")
+	}
+	if len(executor.calls) != 1 {
+		t.Errorf("Expected one update-alternatives call, got %v", executor.calls)
+	}
+}
@@ -0,0 +1,105 @@
+package managers
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// Asdf implements the PackageManager interface for the asdf version manager
+type Asdf struct {
+	executor system.CommandExecutor
+	dataDir  string
+	workDir  string
+}
+
+// NewAsdf creates a new asdf package manager
+func NewAsdf(executor system.CommandExecutor) *Asdf {
+	dataDir := os.Getenv("ASDF_DATA_DIR")
+	if dataDir == "" {
+		dataDir = filepath.Join(system.GetHomeDir(), ".asdf")
+	}
+
+	workDir, _ := os.Getwd()
+
+	return &Asdf{
+		executor: executor,
+		dataDir:  dataDir,
+		workDir:  workDir,
+	}
+}
+
+// Name returns the name of the package manager
+func (a *Asdf) Name() string {
+	return "asdf"
+}
+
+// IsAvailable checks if asdf has an installs directory
+func (a *Asdf) IsAvailable(ctx context.Context) bool {
+	info, err := os.Stat(filepath.Join(a.dataDir, "installs"))
+	return err == nil && info.IsDir()
+}
+
+// Scan discovers all tool versions installed by asdf
+func (a *Asdf) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	requests := findToolRequests(a.workDir, func(dir string) toolRequests {
+		return parseToolVersions(filepath.Join(dir, a.toolVersionsFilename()))
+	})
+	// The global version file lives in $HOME, which may not be an ancestor of workDir
+	requests.merge(parseToolVersions(filepath.Join(system.GetHomeDir(), a.toolVersionsFilename())))
+
+	active := requests.activeVersion("ASDF")
+
+	shimsDir := filepath.Join(a.dataDir, "shims")
+	shimTool := func(shim string, providers []string) string {
+		if plugin := readAsdfShimPlugin(filepath.Join(shimsDir, shim)); plugin != "" {
+			return plugin
+		}
+		if len(providers) > 0 {
+			return providers[0]
+		}
+		return ""
+	}
+
+	installsDir := filepath.Join(a.dataDir, "installs")
+	versionDir := func(tool, version string) string {
+		return filepath.Join(installsDir, tool, version)
+	}
+
+	return versionManagerInstalls(a.Name(), readInstalls(installsDir), versionDir, shimsDir, shimTool, active), nil
+}
+
+// toolVersionsFilename returns the version file name, which users may override
+func (a *Asdf) toolVersionsFilename() string {
+	if name := os.Getenv("ASDF_DEFAULT_TOOL_VERSIONS_FILENAME"); name != "" {
+		return name
+	}
+	return toolVersionsFile
+}
+
+// readAsdfShimPlugin reads the plugin name from the "# asdf-plugin: <plugin> <version>"
+// comments asdf writes into each shim script
+func readAsdfShimPlugin(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		line := lineScanner.Text()
+		if rest, ok := strings.CutPrefix(line, "# asdf-plugin:"); ok {
+			if fields := strings.Fields(rest); len(fields) > 0 {
+				return fields[0]
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,158 @@
+package managers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// Bun implements the PackageManager interface for packages installed with
+// `bun install -g`
+type Bun struct {
+	executor  system.CommandExecutor
+	binDir    string
+	globalDir string
+}
+
+// NewBun creates a new Bun package manager
+func NewBun(executor system.CommandExecutor) *Bun {
+	root := os.Getenv("BUN_INSTALL")
+	if root == "" {
+		root = filepath.Join(system.GetHomeDir(), ".bun")
+	}
+
+	binDir := os.Getenv("BUN_INSTALL_BIN")
+	if binDir == "" {
+		binDir = filepath.Join(root, "bin")
+	}
+
+	globalDir := os.Getenv("BUN_INSTALL_GLOBAL_DIR")
+	if globalDir == "" {
+		globalDir = filepath.Join(root, "install", "global")
+	}
+
+	return &Bun{
+		executor:  executor,
+		binDir:    binDir,
+		globalDir: globalDir,
+	}
+}
+
+// Name returns the name of the package manager
+func (b *Bun) Name() string {
+	return "bun"
+}
+
+// IsAvailable checks if Bun's bin directory exists
+func (b *Bun) IsAvailable(ctx context.Context) bool {
+	return isDir(b.binDir)
+}
+
+// Scan attributes each executable in Bun's bin directory to the global
+// package it runs. Bun links executables into
+// <global>/node_modules/<package>; wrapper scripts name that path instead.
+// Executables that are neither, nor Bun itself, are reported as ghosts.
+func (b *Bun) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	nodeModules := filepath.Join(b.globalDir, "node_modules")
+	if resolved, err := filepath.EvalSymlinks(nodeModules); err == nil {
+		nodeModules = resolved
+	}
+
+	var binaries []*scanner.Binary
+	bunVersion, askedVersion := "", false
+	for _, name := range system.NewFileValidator().ListExecutables(b.binDir) {
+		path := filepath.Join(b.binDir, name)
+
+		if name == "bun" || name == "bunx" {
+			// bunx is the same executable, so bun is only asked once
+			if !askedVersion {
+				bunVersion, askedVersion = b.bunVersion(ctx, filepath.Join(b.binDir, "bun")), true
+			}
+			binaries = append(binaries, &scanner.Binary{
+				Name:    name,
+				Path:    path,
+				Manager: b.Name(),
+				Version: bunVersion,
+				Package: "bun",
+			})
+			continue
+		}
+
+		pkg := bunPackage(path, nodeModules)
+		if pkg == "" {
+			binaries = append(binaries, ghostBinary(name, path))
+			continue
+		}
+
+		binaries = append(binaries, &scanner.Binary{
+			Name:    name,
+			Path:    path,
+			Manager: b.Name(),
+			Version: readPackageJSONVersion(filepath.Join(nodeModules, pkg)),
+			Package: pkg,
+		})
+	}
+
+	return binaries, nil
+}
+
+// bunVersion asks the bun executable for its version
+func (b *Bun) bunVersion(ctx context.Context, path string) string {
+	output, err := b.executor.Execute(ctx, path, "--version")
+	if err != nil {
+		return ""
+	}
+
+	// 1.1.20
+	return strings.TrimSpace(output)
+}
+
+// bunPackage returns the global package an executable belongs to, from its
+// link target or, for wrapper scripts, the path they run
+func bunPackage(path, nodeModules string) string {
+	target := resolveSymlink(path)
+	if target == "" {
+		data, err := os.ReadFile(path)
+		if err != nil || !strings.HasPrefix(string(data), "#!") {
+			return ""
+		}
+		target = string(data)
+	}
+
+	_, rest, ok := strings.Cut(target, nodeModules+string(filepath.Separator))
+	if !ok {
+		return ""
+	}
+	return packageFromPath(rest)
+}
+
+// packageFromPath returns the package name at the start of a path relative
+// to node_modules, e.g. "@biomejs/biome" for @biomejs/biome/bin/biome
+func packageFromPath(rel string) string {
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 3)
+	if strings.HasPrefix(parts[0], "@") && len(parts) > 1 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+// readPackageJSONVersion reads the version from a package's package.json
+func readPackageJSONVersion(packageDir string) string {
+	data, err := os.ReadFile(filepath.Join(packageDir, "package.json"))
+	if err != nil {
+		return ""
+	}
+
+	var manifest struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(data, &manifest) != nil {
+		return ""
+	}
+	return manifest.Version
+}
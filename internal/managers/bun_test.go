@@ -0,0 +1,45 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestBunScan(t *testing.T) {
+	root := t.TempDir()
+	binDir := filepath.Join(root, "bin")
+	globalDir := filepath.Join(root, "install", "global")
+
+	biome := filepath.Join(globalDir, "node_modules", "@biomejs", "biome")
+	writeFile(t, filepath.Join(biome, "package.json"), `{"name": "@biomejs/biome", "version": "1.8.3"}`, 0644)
+	writeFile(t, filepath.Join(biome, "bin", "biome"), "#!/usr/bin/env node\n", 0755)
+	symlink(t, filepath.Join(biome, "bin", "biome"), filepath.Join(binDir, "biome"))
+	writeFile(t, filepath.Join(binDir, "bun"), "\x7fELF", 0755)
+	symlink(t, filepath.Join(binDir, "bun"), filepath.Join(binDir, "bunx"))
+	writeFile(t, filepath.Join(binDir, "leftover"), "#!/bin/sh\necho hi\n", 0755)
+
+	executor := &fakeExecutor{outputs: map[string]string{
+		filepath.Join(binDir, "bun") + " --version": "1.1.20\n",
+	}}
+
+	binaries, err := (&Bun{executor: executor, binDir: binDir, globalDir: globalDir}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if binary := findBinary(binaries, "biome"); binary == nil || binary.Package != "@biomejs/biome" || binary.Version != "1.8.3" {
+		t.Errorf("Unexpected biome: %+v", binary)
+	}
+	for _, name := range []string{"bun", "bunx"} {
+		if binary := findBinary(binaries, name); binary == nil || binary.IsGhost() || binary.Version != "1.1.20" {
+			t.Errorf("Expected %s to be attributed to bun 1.1.20, got %+v", name, binary)
+		}
+	}
+	if len(executor.calls) != 1 {
+		t.Errorf("Expected bun to be asked for its version once, got %v", executor.calls)
+	}
+	if binary := findBinary(binaries, "leftover"); binary == nil || !binary.IsGhost() {
+		t.Errorf("Expected leftover to be a ghost, got %+v", binary)
+	}
+}
@@ -0,0 +1,283 @@
+package managers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// bundleNameVersionPattern finds the version in a bundle directory named
+// like node-v20.11.0-linux-x64 or idea-IU-233.13135.103
+var bundleNameVersionPattern = regexp.MustCompile(`(?:^|[-_])v?(\d+(?:\.\d+)+)(?:[-_]|$)`)
+
+// bundleVersionFiles are the files a bundle's version is read from, in
+// order: Go's VERSION, JDK's release, JetBrains' product-info.json
+var bundleVersionFiles = []string{"VERSION", "version", "version.txt", "release", "product-info.json"}
+
+// ignoredOptDirs are /opt directories that belong to a package manager
+// rather than being an application bundle
+var ignoredOptDirs = map[string]bool{
+	"homebrew": true,
+	"local":    true, // MacPorts
+	"stow":     true,
+}
+
+// managedDirMarkers are entries whose presence shows a directory is the
+// root of a package manager, whatever it's called, rather than a bundle
+var managedDirMarkers = []string{
+	"conda-meta", // conda, mamba and micromamba, e.g. /opt/miniforge3
+	"Cellar",     // Homebrew
+	".stow",      // A stow directory marked for stow's protection
+}
+
+// Bundles implements the PackageManager interface for self-contained
+// application bundles and tarball SDKs: each directory in /opt, plus
+// /usr/local/go and /usr/local/node-*, is treated as a package that owns
+// every binary linked into it
+type Bundles struct {
+	executor system.CommandExecutor
+	roots    []string // Directories whose subdirectories are bundles
+	bundles  []string // Bundle directories
+	binDirs  []string // Directories links into bundles are found in
+	pathDirs []string // PATH, whose directories inside a bundle are scanned too
+}
+
+// bundle is a pseudo-package: a bundle directory and its version
+type bundle struct {
+	Name    string
+	Dir     string
+	Version string
+}
+
+// NewBundles creates a new bundles package manager
+func NewBundles(executor system.CommandExecutor) *Bundles {
+	bundles := []string{"/usr/local/go"}
+	if nodes, err := filepath.Glob("/usr/local/node-*"); err == nil {
+		bundles = append(bundles, nodes...)
+	}
+
+	return &Bundles{
+		executor: executor,
+		roots:    []string{"/opt"},
+		bundles:  bundles,
+		binDirs:  system.GetCommonBinaryPaths(),
+		pathDirs: system.GetPATH(),
+	}
+}
+
+// Name returns the name of the package manager
+func (b *Bundles) Name() string {
+	return "bundle"
+}
+
+// IsAvailable checks if any bundle directory exists
+func (b *Bundles) IsAvailable(ctx context.Context) bool {
+	return len(b.findBundles()) > 0
+}
+
+// Scan attributes every executable in the bin directories, or linked into
+// them, that resolves into a bundle to that bundle. Bin directories inside
+// a bundle, such as /usr/local/go/bin on PATH, are attributed too. An
+// executable reachable both ways is reported once, by its link.
+func (b *Bundles) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	bundles := b.findBundles()
+	if len(bundles) == 0 {
+		return nil, nil
+	}
+
+	dirs := append([]string{}, b.binDirs...)
+	for _, dir := range b.pathDirs {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil && owningBundle(bundles, resolved) != nil {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	validator := system.NewFileValidator()
+	seenDirs := make(map[string]bool)
+	seenTargets := make(map[string]bool)
+
+	var binaries []*scanner.Binary
+	for _, dir := range dirs {
+		if seenDirs[filepath.Clean(dir)] {
+			continue
+		}
+		seenDirs[filepath.Clean(dir)] = true
+
+		for _, name := range validator.ListExecutables(dir) {
+			path := filepath.Join(dir, name)
+			resolved, err := filepath.EvalSymlinks(path)
+			if err != nil || seenTargets[resolved] {
+				continue
+			}
+
+			owner := owningBundle(bundles, resolved)
+			if owner == nil {
+				continue
+			}
+			seenTargets[resolved] = true
+
+			binaries = append(binaries, &scanner.Binary{
+				Name:    name,
+				Path:    path,
+				Manager: b.Name(),
+				Version: owner.Version,
+				Package: owner.Name,
+				Source:  owner.Dir,
+			})
+		}
+	}
+
+	return binaries, nil
+}
+
+// findBundles lists the bundle directories that exist, resolved so links
+// can be matched against them, with their versions
+func (b *Bundles) findBundles() []*bundle {
+	dirs := append([]string{}, b.bundles...)
+	for _, root := range b.roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			dir := filepath.Join(root, entry.Name())
+			if ignoredOptDirs[entry.Name()] || strings.HasPrefix(entry.Name(), ".") || isManagedDir(dir) {
+				continue
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+
+	var bundles []*bundle
+	for _, dir := range dirs {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err != nil || !isDir(resolved) {
+			continue
+		}
+		bundles = append(bundles, &bundle{
+			Name:    filepath.Base(dir),
+			Dir:     resolved,
+			Version: bundleVersion(resolved),
+		})
+	}
+	return bundles
+}
+
+// isManagedDir reports whether dir is the root of a package manager
+func isManagedDir(dir string) bool {
+	for _, marker := range managedDirMarkers {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// owningBundle returns the bundle a resolved path is in, or nil
+func owningBundle(bundles []*bundle, path string) *bundle {
+	for _, b := range bundles {
+		if path == b.Dir || strings.HasPrefix(path, b.Dir+string(filepath.Separator)) {
+			return b
+		}
+	}
+	return nil
+}
+
+// BundleOverlaps drops the bundle records of executables another package
+// manager also reports, such as an Lmod tree in /opt/apps or files Portage
+// installed into /opt, so the same file isn't taken for a conflict
+type BundleOverlaps struct{}
+
+// NewBundleOverlaps creates a bundle overlap filter
+func NewBundleOverlaps() *BundleOverlaps {
+	return &BundleOverlaps{}
+}
+
+// Analyze removes bundle records whose path, or the file it resolves to, is
+// claimed by another manager, and detects conflicts again when any are
+func (o *BundleOverlaps) Analyze(result *scanner.ScanResult) {
+	claimed := make(map[string]bool)
+	for _, binary := range result.Binaries {
+		if binary.Manager == "bundle" || binary.IsGhost() {
+			continue
+		}
+		claimed[binary.Path] = true
+		if resolved, err := filepath.EvalSymlinks(binary.Path); err == nil {
+			claimed[resolved] = true
+		}
+	}
+
+	kept := make([]*scanner.Binary, 0, len(result.Binaries))
+	for _, binary := range result.Binaries {
+		if binary.Manager == "bundle" {
+			resolved, _ := filepath.EvalSymlinks(binary.Path)
+			if claimed[binary.Path] || claimed[resolved] {
+				continue
+			}
+		}
+		kept = append(kept, binary)
+	}
+
+	if len(kept) != len(result.Binaries) {
+		result.Binaries = kept
+		result.DetectConflicts()
+	}
+}
+
+// bundleVersion reads a bundle's version from its version or release
+// file, falling back to a version in its directory name
+func bundleVersion(dir string) string {
+	for _, file := range bundleVersionFiles {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			continue
+		}
+		if version := parseBundleVersion(file, string(data)); version != "" {
+			return version
+		}
+	}
+
+	if match := bundleNameVersionPattern.FindStringSubmatch(filepath.Base(dir)); match != nil {
+		return match[1]
+	}
+	return "unknown"
+}
+
+// parseBundleVersion extracts the version from the contents of one of the
+// bundleVersionFiles
+func parseBundleVersion(file, data string) string {
+	switch file {
+	case "release":
+		// KEY="value" lines, as JDKs and some SDKs write them
+		for _, line := range strings.Split(data, "\n") {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+			if ok && (key == "JAVA_VERSION" || key == "VERSION") {
+				return strings.Trim(value, `"'`)
+			}
+		}
+		return ""
+	case "product-info.json":
+		var info struct {
+			Version string `json:"version"`
+		}
+		if json.Unmarshal([]byte(data), &info) != nil {
+			return ""
+		}
+		return info.Version
+	}
+
+	// A bare version, possibly prefixed like go1.22.0 or v1.2.3, on the
+	// first line
+	line, _, _ := strings.Cut(strings.TrimSpace(data), "\n")
+	version := strings.TrimLeft(strings.TrimSpace(line), "abcdefghijklmnopqrstuvwxyz")
+	if version == "" || version[0] < '0' || version[0] > '9' || strings.ContainsAny(version, " \t") {
+		return ""
+	}
+	return version
+}
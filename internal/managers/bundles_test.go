@@ -0,0 +1,130 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+func TestBundlesScan(t *testing.T) {
+	root := t.TempDir()
+	opt := filepath.Join(root, "opt")
+	bin := filepath.Join(root, "usr", "local", "bin")
+	goRoot := filepath.Join(root, "usr", "local", "go")
+	nodeRoot := filepath.Join(root, "usr", "local", "node-v20.11.0-linux-x64")
+
+	writeFile(t, filepath.Join(opt, "nvim-linux64", "bin", "nvim"), "", 0755)
+	symlink(t, "../../../opt/nvim-linux64/bin/nvim", filepath.Join(bin, "nvim"))
+	writeFile(t, filepath.Join(opt, "idea", "bin", "idea.sh"), "", 0755)
+	writeFile(t, filepath.Join(opt, "idea", "product-info.json"), `{"name": "IntelliJ IDEA", "version": "2023.3.2"}`, 0644)
+	symlink(t, "../../../opt/idea/bin/idea.sh", filepath.Join(bin, "idea"))
+	writeFile(t, filepath.Join(opt, "homebrew", "bin", "brew"), "", 0755)
+	symlink(t, "../../../opt/homebrew/bin/brew", filepath.Join(bin, "brew"))
+	writeFile(t, filepath.Join(bin, "mystery"), "", 0755)
+
+	// Go is on PATH directly and linked into /usr/local/bin
+	writeFile(t, filepath.Join(goRoot, "VERSION"), "go1.22.0\ntime 2024-02-01T00:00:00Z\n", 0644)
+	writeFile(t, filepath.Join(goRoot, "bin", "go"), "", 0755)
+	writeFile(t, filepath.Join(goRoot, "bin", "gofmt"), "", 0755)
+	symlink(t, "../go/bin/go", filepath.Join(bin, "go"))
+	writeFile(t, filepath.Join(nodeRoot, "bin", "node"), "", 0755)
+
+	b := &Bundles{
+		roots:    []string{opt},
+		bundles:  []string{goRoot, nodeRoot},
+		binDirs:  []string{bin},
+		pathDirs: []string{filepath.Join(goRoot, "bin"), filepath.Join(nodeRoot, "bin"), "/usr/bin"},
+	}
+	binaries, err := b.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"nvim":  "nvim-linux64 unknown " + filepath.Join(bin, "nvim"),
+		"idea":  "idea 2023.3.2 " + filepath.Join(bin, "idea"),
+		"go":    "go 1.22.0 " + filepath.Join(bin, "go"),
+		"gofmt": "go 1.22.0 " + filepath.Join(goRoot, "bin", "gofmt"),
+		"node":  "node-v20.11.0-linux-x64 20.11.0 " + filepath.Join(nodeRoot, "bin", "node"),
+	}
+	if len(binaries) != len(expected) {
+		t.Errorf("Expected %d binaries, got %d", len(expected), len(binaries))
+	}
+	for _, binary := range binaries {
+		if got := binary.Package + " " + binary.Version + " " + binary.Path; got != expected[binary.Name] {
+			t.Errorf("Expected %s to be %q, got %q", binary.Name, expected[binary.Name], got)
+		}
+	}
+}
+
+func TestParseBundleVersion(t *testing.T) {
+	tests := []struct {
+		file     string
+		data     string
+		expected string
+	}{
+		{"VERSION", "go1.21.6\ntime 2024-01-05T21:07:37Z\n", "1.21.6"},
+		{"version", "v0.9.5\n", "0.9.5"},
+		{"version.txt", "Built on some machine\n", ""},
+		{"release", "IMPLEMENTOR=\"Eclipse Adoptium\"\nJAVA_VERSION=\"17.0.9\"\n", "17.0.9"},
+		{"product-info.json", `{"version": "2023.3"}`, "2023.3"},
+	}
+
+	for _, tt := range tests {
+		if got := parseBundleVersion(tt.file, tt.data); got != tt.expected {
+			t.Errorf("parseBundleVersion(%q): expected %q, got %q", tt.file, tt.expected, got)
+		}
+	}
+}
+
+func TestBundlesSkipOtherManagers(t *testing.T) {
+	root := t.TempDir()
+	opt := filepath.Join(root, "opt")
+
+	// A conda root reached through CONDA_EXE is conda's, whatever its name
+	writeFile(t, filepath.Join(opt, "miniforge3", "conda-meta", "history"), "", 0644)
+	writeFile(t, filepath.Join(opt, "miniforge3", "bin", "python"), "", 0755)
+	// An Lmod tree is a bundle until the module manager's records are seen
+	appBin := filepath.Join(opt, "apps", "gcc", "13.2.0", "bin")
+	writeFile(t, filepath.Join(appBin, "gcc"), "", 0755)
+	writeFile(t, filepath.Join(opt, "nvim-linux64", "bin", "nvim"), "", 0755)
+
+	b := &Bundles{
+		roots:    []string{opt},
+		pathDirs: []string{filepath.Join(opt, "miniforge3", "bin"), appBin, filepath.Join(opt, "nvim-linux64", "bin")},
+	}
+	binaries, err := b.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findBinary(binaries, "python") != nil {
+		t.Error("Expected the conda root not to be reported as a bundle")
+	}
+	if gcc := findBinary(binaries, "gcc"); gcc == nil || gcc.Package != "apps" {
+		t.Fatalf("Expected gcc to be reported by the apps bundle, got %+v", gcc)
+	}
+
+	result := scanner.NewScanResult()
+	for _, binary := range binaries {
+		result.AddBinary(binary)
+	}
+	result.AddBinary(&scanner.Binary{Name: "gcc", Path: filepath.Join(appBin, "gcc"), Manager: "module", Package: "gcc", Version: "13.2.0"})
+	result.DetectConflicts()
+	if len(result.Conflicts["gcc"]) != 2 {
+		t.Fatalf("Expected the overlap to show as a conflict before analysis, got %v", result.Conflicts)
+	}
+
+	NewBundleOverlaps().Analyze(result)
+
+	if gcc := findBinary(result.Binaries, "gcc"); gcc == nil || gcc.Manager != "module" {
+		t.Errorf("Expected only the module's gcc to remain, got %+v", gcc)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Errorf("Expected no conflicts once the bundle record is dropped, got %v", result.Conflicts)
+	}
+	if findBinary(result.Binaries, "nvim") == nil {
+		t.Error("Expected unclaimed bundle binaries to be kept")
+	}
+}
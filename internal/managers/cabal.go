@@ -0,0 +1,184 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// storeUnitPattern matches a cabal store unit directory,
+// <package>-<version>-<hash>, where exe units also name their component
+var storeUnitPattern = regexp.MustCompile(`^(.+?)-(\d+(?:\.\d+)*)-(.+)$`)
+
+// Cabal implements the PackageManager interface for executables installed
+// with `cabal install`
+type Cabal struct {
+	executor  system.CommandExecutor
+	binDirs   []string
+	storeDirs []string
+}
+
+// cabalUnit is an executable built into the cabal store
+type cabalUnit struct {
+	Package    string
+	Version    string
+	GHCVersion string
+	Path       string
+}
+
+// NewCabal creates a new cabal package manager
+func NewCabal(executor system.CommandExecutor) *Cabal {
+	home := system.GetHomeDir()
+
+	cabalDir := os.Getenv("CABAL_DIR")
+	if cabalDir == "" {
+		cabalDir = filepath.Join(home, ".cabal")
+	}
+
+	// cabal 3.10 uses XDG directories when ~/.cabal doesn't exist
+	return &Cabal{
+		executor: executor,
+		binDirs: []string{
+			filepath.Join(cabalDir, "bin"),
+			filepath.Join(home, ".local", "bin"),
+		},
+		storeDirs: []string{
+			filepath.Join(cabalDir, "store"),
+			filepath.Join(xdgDir("", "XDG_STATE_HOME", filepath.Join(".local", "state"), "cabal"), "store"),
+		},
+	}
+}
+
+// Name returns the name of the package manager
+func (c *Cabal) Name() string {
+	return "cabal"
+}
+
+// IsAvailable checks if cabal has a package store
+func (c *Cabal) IsAvailable(ctx context.Context) bool {
+	for _, dir := range c.storeDirs {
+		if isDir(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan attributes executables in the install directories to the store
+// units they were built in. The default install method symlinks into the
+// store; copies are matched to a store executable of the same name and size.
+func (c *Cabal) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	units := c.readStore()
+
+	var binaries []*scanner.Binary
+	for _, dir := range c.binDirs {
+		for _, name := range system.NewFileValidator().ListExecutables(dir) {
+			path := filepath.Join(dir, name)
+
+			unit := c.linkedUnit(path)
+			if unit == nil {
+				unit = copiedUnit(path, units[name])
+			}
+			if unit == nil {
+				continue
+			}
+
+			binaries = append(binaries, &scanner.Binary{
+				Name:    name,
+				Path:    path,
+				Manager: c.Name(),
+				Version: unit.Version,
+				Package: unit.Package,
+				Notes:   []string{"built with GHC " + unit.GHCVersion},
+			})
+		}
+	}
+
+	return binaries, nil
+}
+
+// readStore indexes the executables in every store by name. Units live at
+// <store>/ghc-<version>/<unit>/bin/<executable>.
+func (c *Cabal) readStore() map[string][]*cabalUnit {
+	units := make(map[string][]*cabalUnit)
+	for _, store := range c.storeDirs {
+		executables, _ := filepath.Glob(filepath.Join(store, "ghc-*", "*", "bin", "*"))
+		for _, path := range executables {
+			if unit := parseStorePath(store, path); unit != nil {
+				units[filepath.Base(path)] = append(units[filepath.Base(path)], unit)
+			}
+		}
+	}
+	return units
+}
+
+// linkedUnit returns the store unit an install symlink points into
+func (c *Cabal) linkedUnit(path string) *cabalUnit {
+	target := resolveSymlink(path)
+	if target == "" {
+		return nil
+	}
+
+	for _, store := range c.storeDirs {
+		resolvedStore, err := filepath.EvalSymlinks(store)
+		if err != nil {
+			continue
+		}
+		if unit := parseStorePath(resolvedStore, target); unit != nil {
+			return unit
+		}
+	}
+	return nil
+}
+
+// parseStorePath reads the package, version and compiler from the path of
+// an executable inside a store
+func parseStorePath(store, path string) *cabalUnit {
+	rel, err := filepath.Rel(store, path)
+	if err != nil {
+		return nil
+	}
+
+	parts := strings.Split(rel, string(filepath.Separator))
+	if len(parts) != 4 || parts[2] != "bin" || !strings.HasPrefix(parts[0], "ghc-") {
+		return nil
+	}
+
+	match := storeUnitPattern.FindStringSubmatch(parts[1])
+	if match == nil {
+		return nil
+	}
+
+	return &cabalUnit{
+		Package:    match[1],
+		Version:    match[2],
+		GHCVersion: strings.TrimPrefix(parts[0], "ghc-"),
+		Path:       path,
+	}
+}
+
+// copiedUnit returns the newest candidate whose executable has the same
+// size as a copy installed with --install-method=copy
+func copiedUnit(path string, candidates []*cabalUnit) *cabalUnit {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	var best *cabalUnit
+	for _, unit := range candidates {
+		unitInfo, err := os.Stat(unit.Path)
+		if err != nil || unitInfo.Size() != info.Size() {
+			continue
+		}
+		if best == nil || compareVersions(unit.Version, best.Version) > 0 {
+			best = unit
+		}
+	}
+	return best
+}
@@ -0,0 +1,40 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestCabalScan(t *testing.T) {
+	store := t.TempDir()
+	binDir := t.TempDir()
+
+	unitBin := filepath.Join(store, "ghc-9.4.8", "hlint-3.6.1-e-hlint-0a1b2c", "bin")
+	writeFile(t, filepath.Join(unitBin, "hlint"), "hlint", 0755)
+	writeFile(t, filepath.Join(store, "ghc-9.4.8", "fourmolu-0.14.0.0-9f8e7d", "bin", "fourmolu"), "fourmolu", 0755)
+
+	symlink(t, filepath.Join(unitBin, "hlint"), filepath.Join(binDir, "hlint"))
+	writeFile(t, filepath.Join(binDir, "fourmolu"), "fourmolu", 0755)
+	writeFile(t, filepath.Join(binDir, "unrelated"), "", 0755)
+
+	binaries, err := (&Cabal{binDirs: []string{binDir}, storeDirs: []string{store}}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make(map[string]string)
+	for _, binary := range binaries {
+		found[binary.Name] = binary.Package + " " + binary.Version
+	}
+
+	if found["hlint"] != "hlint 3.6.1" {
+		t.Errorf("Expected symlinked hlint 3.6.1, got %q", found["hlint"])
+	}
+	if found["fourmolu"] != "fourmolu 0.14.0.0" {
+		t.Errorf("Expected copied fourmolu 0.14.0.0, got %q", found["fourmolu"])
+	}
+	if _, ok := found["unrelated"]; ok {
+		t.Errorf("Expected unrelated executable to be ignored")
+	}
+}
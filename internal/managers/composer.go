@@ -0,0 +1,113 @@
+package managers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// Composer implements the PackageManager interface for PHP packages
+// installed with `composer global require`
+type Composer struct {
+	executor system.CommandExecutor
+	home     string
+}
+
+// composerPackage is an entry of Composer's installed.json
+type composerPackage struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Bin     []string `json:"bin"`
+}
+
+// NewComposer creates a new Composer global package manager
+func NewComposer(executor system.CommandExecutor) *Composer {
+	home := xdgDir("COMPOSER_HOME", "XDG_CONFIG_HOME", ".config", "composer")
+	if legacy := filepath.Join(system.GetHomeDir(), ".composer"); !isDir(home) && isDir(legacy) {
+		home = legacy
+	}
+
+	return &Composer{
+		executor: executor,
+		home:     home,
+	}
+}
+
+// Name returns the name of the package manager
+func (c *Composer) Name() string {
+	return "composer"
+}
+
+// IsAvailable checks if the global vendor bin directory exists
+func (c *Composer) IsAvailable(ctx context.Context) bool {
+	return isDir(c.binDir())
+}
+
+// binDir returns the directory Composer links global executables into
+func (c *Composer) binDir() string {
+	return filepath.Join(c.home, "vendor", "bin")
+}
+
+// Scan attributes each executable in the global vendor bin directory to
+// the package declaring it in installed.json. Executables no package
+// declares are reported as ghosts.
+func (c *Composer) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	owners := make(map[string]composerPackage)
+	for _, pkg := range readComposerInstalled(filepath.Join(c.home, "vendor", "composer", "installed.json")) {
+		for _, bin := range pkg.Bin {
+			owners[filepath.Base(bin)] = pkg
+		}
+	}
+
+	var binaries []*scanner.Binary
+	for _, name := range system.NewFileValidator().ListExecutables(c.binDir()) {
+		path := filepath.Join(c.binDir(), name)
+
+		pkg, ok := owners[name]
+		if !ok {
+			// Windows proxies sit next to the real executable
+			if strings.HasSuffix(name, ".bat") {
+				continue
+			}
+			binaries = append(binaries, ghostBinary(name, path))
+			continue
+		}
+
+		binaries = append(binaries, &scanner.Binary{
+			Name:    name,
+			Path:    path,
+			Manager: c.Name(),
+			Version: strings.TrimPrefix(pkg.Version, "v"),
+			Package: pkg.Name,
+		})
+	}
+
+	return binaries, nil
+}
+
+// readComposerInstalled reads installed.json, a list of packages in
+// Composer 1 and an object with a packages list since Composer 2
+func readComposerInstalled(path string) []composerPackage {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var installed struct {
+		Packages []composerPackage `json:"packages"`
+	}
+	if json.Unmarshal(data, &installed) == nil {
+		return installed.Packages
+	}
+
+	var packages []composerPackage
+	if json.Unmarshal(data, &packages) != nil {
+		return nil
+	}
+	return packages
+}
@@ -0,0 +1,40 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestComposerScan(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed string
+	}{
+		{"composer 2", `{"packages": [{"name": "laravel/installer", "version": "v5.2.0", "bin": ["bin/laravel"]}, {"name": "psr/log", "version": "3.0.0"}], "dev": true}`},
+		{"composer 1", `[{"name": "laravel/installer", "version": "v5.2.0", "bin": ["bin/laravel"]}]`},
+	}
+
+	for _, tt := range tests {
+		home := t.TempDir()
+		writeFile(t, filepath.Join(home, "vendor", "composer", "installed.json"), tt.installed, 0644)
+		writeFile(t, filepath.Join(home, "vendor", "laravel", "installer", "bin", "laravel"), "#!/usr/bin/env php\n", 0755)
+		symlink(t, "../laravel/installer/bin/laravel", filepath.Join(home, "vendor", "bin", "laravel"))
+		writeFile(t, filepath.Join(home, "vendor", "bin", "laravel.bat"), "", 0755)
+		writeFile(t, filepath.Join(home, "vendor", "bin", "leftover"), "", 0755)
+
+		binaries, err := (&Composer{home: home}).Scan(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(binaries) != 2 {
+			t.Errorf("%s: expected 2 binaries, got %d", tt.name, len(binaries))
+		}
+		if laravel := findBinary(binaries, "laravel"); laravel == nil || laravel.Package != "laravel/installer" || laravel.Version != "5.2.0" {
+			t.Errorf("%s: expected laravel/installer 5.2.0, got %+v", tt.name, laravel)
+		}
+		if leftover := findBinary(binaries, "leftover"); leftover == nil || !leftover.IsGhost() {
+			t.Errorf("%s: expected leftover to be a ghost, got %+v", tt.name, leftover)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package managers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// condaRootNames lists the directories conda-family installers default to
+var condaRootNames = []string{
+	"miniconda3", "anaconda3", "miniforge3", "mambaforge", "micromamba",
+	filepath.Join("opt", "miniconda3"), filepath.Join("opt", "anaconda3"),
+	filepath.Join(".local", "share", "mamba"),
+}
+
+// Conda implements the PackageManager interface for conda, mamba and
+// micromamba installations and their environments
+type Conda struct {
+	executor system.CommandExecutor
+	homeDir  string
+	roots    []string
+}
+
+// condaEnv is a single environment, either a root's base or one of its envs
+type condaEnv struct {
+	Name string
+	Path string
+	Root string
+}
+
+// NewConda creates a new conda package manager
+func NewConda(executor system.CommandExecutor) *Conda {
+	return &Conda{
+		executor: executor,
+		homeDir:  system.GetHomeDir(),
+	}
+}
+
+// Name returns the name of the package manager
+func (c *Conda) Name() string {
+	return "conda"
+}
+
+// IsAvailable checks if any conda-family installation exists
+func (c *Conda) IsAvailable(ctx context.Context) bool {
+	return len(c.findRoots()) > 0
+}
+
+// Scan inventories every environment from its conda-meta records. The
+// executables of environments on PATH are reported individually; other
+// environments are reported once, as inactive, with their disk usage.
+func (c *Conda) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	var binaries []*scanner.Binary
+
+	autoActivate := c.autoActivateBase()
+	activePrefix := filepath.Clean(os.Getenv("CONDA_PREFIX"))
+	sizes := make(condaSizes)
+
+	for _, env := range c.findEnvs() {
+		onPath := system.IsInPATH(filepath.Join(env.Path, "bin"))
+
+		var reason string
+		switch {
+		case env.Path == activePrefix:
+			reason = "activated with conda activate"
+		case onPath && env.Path == env.Root && autoActivate:
+			reason = "activated by auto_activate_base"
+		case onPath:
+			reason = "bin directory is on PATH"
+		}
+
+		executables := condaEnvExecutables(env.Path)
+		if len(executables) == 0 {
+			continue
+		}
+
+		var notes []string
+		if env.Path == env.Root {
+			if pkgs := filepath.Join(env.Root, "pkgs"); isDir(pkgs) {
+				notes = append(notes, fmt.Sprintf("package cache %s uses %s (conda clean --all)", pkgs, system.FormatSize(sizes.dir(pkgs))))
+			}
+		}
+
+		if reason == "" {
+			binaries = append(binaries, &scanner.Binary{
+				Name:     env.Name,
+				Path:     env.Path,
+				Manager:  c.Name(),
+				Version:  executables[representativeExecutable(executables)].Version,
+				Package:  "environment",
+				Prefix:   env.Path,
+				Inactive: true,
+				Size:     sizes.env(env),
+				Kind:     scanner.KindDirectory,
+				Notes:    append([]string{fmt.Sprintf("conda environment %q is not on PATH", env.Name)}, notes...),
+			})
+			continue
+		}
+
+		shadowed := shadowedExecutables(filepath.Join(env.Path, "bin"))
+		representative := representativeExecutable(executables)
+
+		names := make([]string, 0, len(executables))
+		for name := range executables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			pkg := executables[name]
+			binary := &scanner.Binary{
+				Name:    name,
+				Path:    filepath.Join(env.Path, "bin", name),
+				Manager: c.Name(),
+				Version: pkg.Version,
+				Package: pkg.Name,
+				Prefix:  env.Path,
+			}
+
+			if name == representative {
+				binary.Size = sizes.env(env)
+				binary.Notes = append([]string{fmt.Sprintf("conda environment %q %s", env.Name, reason)}, notes...)
+			}
+			if other, ok := shadowed[name]; ok {
+				binary.Notes = append(binary.Notes, "shadows "+other)
+			}
+
+			binaries = append(binaries, binary)
+		}
+	}
+
+	return binaries, nil
+}
+
+// condaSizes remembers the disk usage of the directories measured during a
+// scan, so that large trees such as the package cache are walked only once
+type condaSizes map[string]int64
+
+// dir returns the disk usage of a directory, measuring it the first time
+func (s condaSizes) dir(path string) int64 {
+	size, ok := s[path]
+	if !ok {
+		size = system.DirSize(path)
+		s[path] = size
+	}
+	return size
+}
+
+// env returns the disk usage of an environment. A base environment's
+// directory also holds the named environments and the package cache, which
+// are measured separately and left out.
+func (s condaSizes) env(e condaEnv) int64 {
+	if e.Path != e.Root {
+		return s.dir(e.Path)
+	}
+
+	entries, err := os.ReadDir(e.Path)
+	if err != nil {
+		return 0
+	}
+
+	var size int64
+	for _, entry := range entries {
+		if entry.Name() == "envs" || entry.Name() == "pkgs" {
+			continue
+		}
+		size += s.dir(filepath.Join(e.Path, entry.Name()))
+	}
+	return size
+}
+
+// findRoots locates conda-family installation roots from the environment
+// variables conda and micromamba set, then the installers' default locations
+func (c *Conda) findRoots() []string {
+	if c.roots != nil {
+		return c.roots
+	}
+
+	var candidates []string
+	if exe := os.Getenv("CONDA_EXE"); exe != "" {
+		candidates = append(candidates, filepath.Dir(filepath.Dir(exe)))
+	}
+	candidates = append(candidates, os.Getenv("MAMBA_ROOT_PREFIX"))
+	for _, name := range condaRootNames {
+		candidates = append(candidates, filepath.Join(c.homeDir, name))
+	}
+	candidates = append(candidates, "/opt/conda", "/opt/miniconda3", "/opt/anaconda3")
+
+	seen := make(map[string]bool)
+	c.roots = []string{}
+	for _, root := range candidates {
+		if root == "" {
+			continue
+		}
+		root = filepath.Clean(root)
+		if seen[root] || !isDir(filepath.Join(root, "conda-meta")) && !isDir(filepath.Join(root, "envs")) {
+			continue
+		}
+		seen[root] = true
+		c.roots = append(c.roots, root)
+	}
+
+	return c.roots
+}
+
+// findEnvs lists the base and named environments of every root, plus any
+// environments registered in ~/.conda/environments.txt
+func (c *Conda) findEnvs() []condaEnv {
+	var envs []condaEnv
+	seen := make(map[string]bool)
+
+	add := func(env condaEnv) {
+		if seen[env.Path] || !isDir(filepath.Join(env.Path, "conda-meta")) {
+			return
+		}
+		seen[env.Path] = true
+		envs = append(envs, env)
+	}
+
+	roots := c.findRoots()
+	for _, root := range roots {
+		add(condaEnv{Name: "base", Path: root, Root: root})
+
+		entries, err := os.ReadDir(filepath.Join(root, "envs"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				add(condaEnv{Name: entry.Name(), Path: filepath.Join(root, "envs", entry.Name()), Root: root})
+			}
+		}
+	}
+
+	file, err := os.Open(filepath.Join(c.homeDir, ".conda", "environments.txt"))
+	if err != nil {
+		return envs
+	}
+	defer file.Close()
+
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		path := filepath.Clean(strings.TrimSpace(lineScanner.Text()))
+		if path == "." {
+			continue
+		}
+
+		root := filepath.Dir(filepath.Dir(path))
+		for _, r := range roots {
+			if r == path {
+				root = r
+			}
+		}
+		add(condaEnv{Name: filepath.Base(path), Path: path, Root: root})
+	}
+
+	return envs
+}
+
+// autoActivateBase reads auto_activate_base from the user's .condarc,
+// which defaults to true
+func (c *Conda) autoActivateBase() bool {
+	data, err := os.ReadFile(filepath.Join(c.homeDir, ".condarc"))
+	if err != nil {
+		return true
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(key) == "auto_activate_base" {
+			return strings.TrimSpace(value) != "false"
+		}
+	}
+	return true
+}
+
+// condaPackage is the subset of a conda-meta/*.json record we use
+type condaPackage struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Files   []string `json:"files"`
+}
+
+// condaEnvExecutables maps each executable an environment installs in its
+// bin directory to the package that provides it
+func condaEnvExecutables(envPath string) map[string]*condaPackage {
+	records, _ := filepath.Glob(filepath.Join(envPath, "conda-meta", "*.json"))
+	validator := system.NewFileValidator()
+	executables := make(map[string]*condaPackage)
+
+	for _, record := range records {
+		data, err := os.ReadFile(record)
+		if err != nil {
+			continue
+		}
+
+		var pkg condaPackage
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			continue
+		}
+
+		for _, file := range pkg.Files {
+			name, ok := strings.CutPrefix(file, "bin/")
+			if !ok || strings.Contains(name, "/") {
+				continue
+			}
+			if validator.IsBinaryExecutable(filepath.Join(envPath, file)) {
+				executables[name] = &pkg
+			}
+		}
+	}
+
+	return executables
+}
+
+// representativeExecutable picks the executable that carries an
+// environment's notes, preferring its Python interpreter
+func representativeExecutable(executables map[string]*condaPackage) string {
+	for _, name := range []string{"python", "python3", "conda", "mamba", "micromamba"} {
+		if _, ok := executables[name]; ok {
+			return name
+		}
+	}
+
+	first := ""
+	for name := range executables {
+		if first == "" || name < first {
+			first = name
+		}
+	}
+	return first
+}
+
+// shadowedExecutables finds executables in binDir that hide another
+// executable of the same name in a later PATH directory
+func shadowedExecutables(binDir string) map[string]string {
+	shadowed := make(map[string]string)
+	validator := system.NewFileValidator()
+
+	found := false
+	for _, dir := range system.GetPATH() {
+		if filepath.Clean(dir) == filepath.Clean(binDir) {
+			found = true
+			continue
+		}
+		if !found {
+			continue
+		}
+
+		for _, name := range validator.ListExecutables(dir) {
+			if _, exists := shadowed[name]; exists {
+				continue
+			}
+			if validator.IsBinaryExecutable(filepath.Join(binDir, name)) {
+				shadowed[name] = filepath.Join(dir, name)
+			}
+		}
+	}
+
+	return shadowed
+}
+
+// isDir reports whether path exists and is a directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,111 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeCondaEnv creates an environment whose conda-meta records a package
+// installing the given executables into bin
+func writeCondaEnv(t *testing.T, env, pkg, version string, executables ...string) {
+	t.Helper()
+	var files []string
+	for _, name := range executables {
+		writeFile(t, filepath.Join(env, "bin", name), "#!/bin/sh\n", 0755)
+		files = append(files, `"bin/`+name+`"`)
+	}
+	writeFile(t, filepath.Join(env, "conda-meta", pkg+"-"+version+".json"),
+		`{"name": "`+pkg+`", "version": "`+version+`", "files": [`+strings.Join(files, ", ")+`]}`, 0644)
+}
+
+func TestCondaScan(t *testing.T) {
+	home := t.TempDir()
+	root := filepath.Join(home, "miniforge3")
+
+	writeCondaEnv(t, root, "python", "3.12.1", "python", "conda")
+	writeFile(t, filepath.Join(root, "pkgs", "python-3.12.1.tar.bz2"), strings.Repeat("x", 2048), 0644)
+	writeCondaEnv(t, filepath.Join(root, "envs", "data"), "numpy", "1.26.4", "f2py")
+	writeCondaEnv(t, filepath.Join(root, "envs", "web"), "nodejs", "20.11.0", "node")
+	writeCondaEnv(t, filepath.Join(home, "envs", "tools"), "ripgrep", "14.1.0", "rg")
+	writeFile(t, filepath.Join(home, ".conda", "environments.txt"), filepath.Join(home, "envs", "tools")+"\n", 0644)
+
+	// A python later on PATH is shadowed by the base environment's
+	usrBin := filepath.Join(home, "usr", "bin")
+	writeFile(t, filepath.Join(usrBin, "python"), "#!/bin/sh\n", 0755)
+
+	t.Setenv("CONDA_PREFIX", filepath.Join(root, "envs", "web"))
+	t.Setenv("PATH", strings.Join([]string{
+		filepath.Join(root, "envs", "web", "bin"),
+		filepath.Join(root, "bin"),
+		usrBin,
+		filepath.Join(home, "envs", "tools", "bin"),
+	}, string(os.PathListSeparator)))
+
+	conda := &Conda{executor: &fakeExecutor{}, homeDir: home, roots: []string{root}}
+	binaries, err := conda.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	python := findBinary(binaries, "python")
+	if python == nil || python.Version != "3.12.1" || python.Inactive || python.Size == 0 {
+		t.Fatalf("Expected the base python to be active with its environment's size, got %+v", python)
+	}
+	notes := strings.Join(python.Notes, "\n")
+	for _, want := range []string{
+		`conda environment "base" activated by auto_activate_base`,
+		"package cache " + filepath.Join(root, "pkgs") + " uses 2.0 KB",
+		"shadows " + filepath.Join(usrBin, "python"),
+	} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("Expected python notes to contain %q, got %q", want, notes)
+		}
+	}
+	// The package cache and named environments aren't counted in base
+	if python.Size >= 2048 {
+		t.Errorf("Expected the base size to leave out the package cache, got %d", python.Size)
+	}
+
+	expected := map[string]string{
+		"node": `conda environment "web" activated with conda activate`,
+		"rg":   `conda environment "tools" bin directory is on PATH`,
+	}
+	for name, note := range expected {
+		binary := findBinary(binaries, name)
+		if binary == nil || binary.Inactive || len(binary.Notes) == 0 || binary.Notes[0] != note {
+			t.Errorf("Expected %s to be active with note %q, got %+v", name, note, binary)
+		}
+	}
+
+	data := findBinary(binaries, "data")
+	if data == nil || !data.Inactive || data.Path != filepath.Join(root, "envs", "data") || data.Version != "1.26.4" {
+		t.Errorf("Expected the data environment to be reported once as inactive, got %+v", data)
+	}
+	if findBinary(binaries, "f2py") != nil {
+		t.Error("Expected the executables of an inactive environment not to be reported")
+	}
+}
+
+func TestCondaAutoActivateBaseDisabled(t *testing.T) {
+	home := t.TempDir()
+	root := filepath.Join(home, "miniconda3")
+	writeCondaEnv(t, root, "python", "3.11.7", "python")
+	writeFile(t, filepath.Join(home, ".condarc"), "auto_activate_base: false\n", 0644)
+
+	t.Setenv("CONDA_PREFIX", "")
+	t.Setenv("PATH", filepath.Join(root, "bin"))
+
+	conda := &Conda{executor: &fakeExecutor{}, homeDir: home, roots: []string{root}}
+	binaries, err := conda.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	python := findBinary(binaries, "python")
+	if python == nil || len(python.Notes) == 0 || python.Notes[0] != `conda environment "base" bin directory is on PATH` {
+		t.Errorf("Expected base to be on PATH without auto_activate_base, got %+v", python)
+	}
+}
@@ -0,0 +1,155 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// Deno implements the PackageManager interface for scripts installed with
+// `deno install`
+type Deno struct {
+	executor system.CommandExecutor
+	binDir   string
+}
+
+// NewDeno creates a new Deno package manager
+func NewDeno(executor system.CommandExecutor) *Deno {
+	root := os.Getenv("DENO_INSTALL_ROOT")
+	if root == "" {
+		root = filepath.Join(system.GetHomeDir(), ".deno")
+	}
+
+	return &Deno{
+		executor: executor,
+		binDir:   filepath.Join(root, "bin"),
+	}
+}
+
+// Name returns the name of the package manager
+func (d *Deno) Name() string {
+	return "deno"
+}
+
+// IsAvailable checks if Deno's bin directory exists
+func (d *Deno) IsAvailable(ctx context.Context) bool {
+	return isDir(d.binDir)
+}
+
+// Scan parses the shell wrapper deno install writes for each script, which
+// runs `deno run` on the installed module. Executables that aren't wrappers
+// or Deno itself are reported as ghosts.
+func (d *Deno) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	var binaries []*scanner.Binary
+	for _, name := range system.NewFileValidator().ListExecutables(d.binDir) {
+		path := filepath.Join(d.binDir, name)
+
+		if name == "deno" {
+			binaries = append(binaries, &scanner.Binary{
+				Name:    name,
+				Path:    path,
+				Manager: d.Name(),
+				Version: d.denoVersion(ctx, path),
+				Package: "deno",
+			})
+			continue
+		}
+
+		module := readDenoWrapper(path)
+		if module == "" {
+			binaries = append(binaries, ghostBinary(name, path))
+			continue
+		}
+
+		pkg, version := splitModuleSpecifier(module)
+		binaries = append(binaries, &scanner.Binary{
+			Name:    name,
+			Path:    path,
+			Manager: d.Name(),
+			Version: version,
+			Package: pkg,
+			Source:  module,
+		})
+	}
+
+	return binaries, nil
+}
+
+// denoVersion asks the deno executable for its version
+func (d *Deno) denoVersion(ctx context.Context, path string) string {
+	output, err := d.executor.Execute(ctx, path, "--version")
+	if err != nil {
+		return ""
+	}
+
+	// deno 1.46.3 (stable, release, x86_64-unknown-linux-gnu)
+	fields := strings.Fields(output)
+	if len(fields) >= 2 && fields[0] == "deno" {
+		return fields[1]
+	}
+	return ""
+}
+
+// readDenoWrapper returns the module a deno install wrapper runs, such as
+// https://deno.land/std@0.200.0/http/file_server.ts or npm:cowsay@1.5.0
+func readDenoWrapper(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasPrefix(string(data), "#!") {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		// The deno path may be quoted, e.g. exec "/home/u/.deno/bin/deno" run …
+		if len(fields) < 3 || fields[0] != "exec" || !strings.HasSuffix(strings.Trim(fields[1], `'"`), "deno") {
+			continue
+		}
+
+		// The module is the first argument that isn't a flag, e.g. --allow-net
+		for _, field := range fields[3:] {
+			field = strings.Trim(field, `'"`)
+			if strings.HasPrefix(field, "-") {
+				continue
+			}
+			if strings.Contains(field, "://") || strings.HasPrefix(field, "npm:") || strings.HasPrefix(field, "jsr:") {
+				return field
+			}
+		}
+	}
+	return ""
+}
+
+// splitModuleSpecifier splits a module URL or npm:/jsr: specifier into the
+// package it names and the version pinned after "@", if any. Scoped names
+// such as @std/http keep their leading "@".
+func splitModuleSpecifier(specifier string) (string, string) {
+	rest := specifier
+	if _, after, ok := strings.Cut(rest, "://"); ok {
+		rest = after
+	} else if scheme, after, ok := strings.Cut(rest, ":"); ok && (scheme == "npm" || scheme == "jsr") {
+		rest = after
+	}
+
+	for i := 1; i < len(rest); i++ {
+		if rest[i] == '@' && rest[i-1] != '/' {
+			version, _, _ := strings.Cut(rest[i+1:], "/")
+			return rest[:i], version
+		}
+	}
+	return rest, ""
+}
+
+// ghostBinary records an executable in a manager's directory that the
+// manager has no record of, the way the manual scanner reports ghosts
+func ghostBinary(name, path string) *scanner.Binary {
+	return &scanner.Binary{
+		Name:    name,
+		Path:    path,
+		Manager: "manual",
+		Version: "unknown",
+	}
+}
@@ -0,0 +1,58 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitModuleSpecifier(t *testing.T) {
+	tests := []struct {
+		specifier string
+		pkg       string
+		version   string
+	}{
+		{"https://deno.land/std@0.200.0/http/file_server.ts", "deno.land/std", "0.200.0"},
+		{"https://deno.land/x/denon@2.5.0/denon.ts", "deno.land/x/denon", "2.5.0"},
+		{"npm:cowsay@1.5.0", "cowsay", "1.5.0"},
+		{"npm:@biomejs/biome@1.8.3/bin/biome", "@biomejs/biome", "1.8.3"},
+		{"jsr:@std/http@1.0.0/file-server", "@std/http", "1.0.0"},
+		{"jsr:@std/http/file-server", "@std/http/file-server", ""},
+		{"file:///home/me/tools/greet.ts", "/home/me/tools/greet.ts", ""},
+	}
+
+	for _, tt := range tests {
+		pkg, version := splitModuleSpecifier(tt.specifier)
+		if pkg != tt.pkg || version != tt.version {
+			t.Errorf("splitModuleSpecifier(%q): expected %s@%s, got %s@%s", tt.specifier, tt.pkg, tt.version, pkg, version)
+		}
+	}
+}
+
+func TestDenoScan(t *testing.T) {
+	binDir := t.TempDir()
+	writeFile(t, filepath.Join(binDir, "file_server"), "#!/bin/sh\n# generated by deno install\nexec deno run --allow-read --allow-net 'https://deno.land/std@0.200.0/http/file_server.ts' \"$@\"\n", 0755)
+	writeFile(t, filepath.Join(binDir, ".file_server.tsconfig.json"), "{}", 0644)
+	writeFile(t, filepath.Join(binDir, "cowsay"), "#!/bin/sh\n# generated by deno install\nexec \"/home/u/.deno/bin/deno\" run --allow-env 'npm:cowsay@1.5.0' \"$@\"\n", 0755)
+	writeFile(t, filepath.Join(binDir, "mystery"), "\x7fELF", 0755)
+
+	binaries, err := (&Deno{executor: &fakeExecutor{}, binDir: binDir}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(binaries) != 3 {
+		t.Fatalf("Expected 3 binaries, got %d", len(binaries))
+	}
+
+	server := findBinary(binaries, "file_server")
+	if server == nil || server.Package != "deno.land/std" || server.Version != "0.200.0" || server.Source != "https://deno.land/std@0.200.0/http/file_server.ts" {
+		t.Errorf("Unexpected file_server: %+v", server)
+	}
+	if cowsay := findBinary(binaries, "cowsay"); cowsay == nil || cowsay.Source != "npm:cowsay@1.5.0" || cowsay.IsGhost() {
+		t.Errorf("Expected cowsay from a quoted deno path to be attributed, got %+v", cowsay)
+	}
+	if mystery := findBinary(binaries, "mystery"); mystery == nil || !mystery.IsGhost() {
+		t.Errorf("Expected mystery to be a ghost, got %+v", mystery)
+	}
+}
@@ -0,0 +1,122 @@
+package managers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// dockerSystemPluginDirs lists where packages install Docker CLI plugins
+var dockerSystemPluginDirs = []string{
+	"/usr/local/lib/docker/cli-plugins",
+	"/usr/local/libexec/docker/cli-plugins",
+	"/usr/lib/docker/cli-plugins",
+	"/usr/libexec/docker/cli-plugins",
+}
+
+// dockerPluginNamePattern matches the plugin names the docker CLI accepts;
+// it ignores docker-* files with any other name, such as docker-compose.bak
+var dockerPluginNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// dockerPluginMetadataTimeout bounds each docker-cli-plugin-metadata run
+const dockerPluginMetadataTimeout = 2 * time.Second
+
+// DockerPlugins implements the PackageManager interface for Docker CLI
+// plugins such as buildx and compose
+type DockerPlugins struct {
+	executor system.CommandExecutor
+	dirs     []string
+}
+
+// dockerPluginMetadata is what a plugin prints for docker-cli-plugin-metadata
+type dockerPluginMetadata struct {
+	Vendor  string `json:"Vendor"`
+	Version string `json:"Version"`
+	URL     string `json:"URL"`
+}
+
+// NewDockerPlugins creates a new Docker CLI plugins package manager
+func NewDockerPlugins(executor system.CommandExecutor) *DockerPlugins {
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		configDir = filepath.Join(system.GetHomeDir(), ".docker")
+	}
+
+	return &DockerPlugins{
+		executor: executor,
+		dirs:     append([]string{filepath.Join(configDir, "cli-plugins")}, dockerSystemPluginDirs...),
+	}
+}
+
+// Name returns the name of the package manager
+func (d *DockerPlugins) Name() string {
+	return "docker"
+}
+
+// IsAvailable checks if any CLI plugin directory exists
+func (d *DockerPlugins) IsAvailable(ctx context.Context) bool {
+	for _, dir := range d.dirs {
+		if isDir(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan reports every docker-<name> plugin, asking each working plugin for
+// its metadata the way the docker CLI does. Dangling links, such as those
+// left behind when Docker Desktop is removed, are reported as broken. Files
+// the docker CLI wouldn't load as plugins are never run.
+func (d *DockerPlugins) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	var binaries []*scanner.Binary
+	for _, dir := range d.dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			plugin, ok := strings.CutPrefix(entry.Name(), "docker-")
+			if !ok || entry.IsDir() || !dockerPluginNamePattern.MatchString(plugin) {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			binary := pluginBinary(d.Name(), entry.Name(), path, "", plugin, "")
+			if !binary.Inactive {
+				d.readMetadata(ctx, binary)
+			}
+			binaries = append(binaries, binary)
+		}
+	}
+
+	return binaries, nil
+}
+
+// readMetadata fills in a plugin's version and URL from its metadata
+func (d *DockerPlugins) readMetadata(ctx context.Context, binary *scanner.Binary) {
+	ctx, cancel := context.WithTimeout(ctx, dockerPluginMetadataTimeout)
+	defer cancel()
+	output, err := d.executor.Execute(ctx, binary.Path, "docker-cli-plugin-metadata")
+
+	var metadata dockerPluginMetadata
+	if err != nil || json.Unmarshal([]byte(output), &metadata) != nil {
+		// The docker CLI skips plugins it can't read metadata from
+		binary.Inactive = true
+		binary.Notes = append(binary.Notes, "broken plugin: no docker-cli-plugin-metadata")
+		return
+	}
+
+	binary.Version = strings.TrimPrefix(metadata.Version, "v")
+	binary.Source = metadata.URL
+	if binary.Source == "" && metadata.Vendor != "" {
+		binary.Source = metadata.Vendor
+	}
+}
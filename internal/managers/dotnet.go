@@ -0,0 +1,267 @@
+package managers
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// dotnetRoots lists where the dotnet installers put the SDKs and runtimes
+var dotnetRoots = []string{
+	"/usr/local/share/dotnet", // macOS installer
+	"/usr/share/dotnet",       // Linux packages
+	"/usr/lib/dotnet",         // Ubuntu packages
+}
+
+// dotnetVersionPattern matches the directory names of SDKs and runtimes,
+// e.g. 8.0.101 or 9.0.100-preview.1.24101.2, so that siblings such as
+// sdk/NuGetFallbackFolder aren't taken for installs
+var dotnetVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?$`)
+
+// DotNet implements the PackageManager interface for .NET global tools and
+// the SDKs and runtimes installed side by side under the dotnet root
+type DotNet struct {
+	executor system.CommandExecutor
+	toolsDir string
+	root     string
+	workDir  string
+}
+
+// NewDotNet creates a new .NET package manager
+func NewDotNet(executor system.CommandExecutor) *DotNet {
+	home := system.GetHomeDir()
+
+	root := os.Getenv("DOTNET_ROOT")
+	if root == "" {
+		for _, candidate := range append([]string{filepath.Join(home, ".dotnet")}, dotnetRoots...) {
+			if isDir(filepath.Join(candidate, "sdk")) || isDir(filepath.Join(candidate, "shared")) {
+				root = candidate
+				break
+			}
+		}
+	}
+
+	workDir, _ := os.Getwd()
+
+	return &DotNet{
+		executor: executor,
+		toolsDir: filepath.Join(home, ".dotnet", "tools"),
+		root:     root,
+		workDir:  workDir,
+	}
+}
+
+// Name returns the name of the package manager
+func (d *DotNet) Name() string {
+	return "dotnet"
+}
+
+// IsAvailable checks if a dotnet root or global tools directory exists
+func (d *DotNet) IsAvailable(ctx context.Context) bool {
+	return (d.root != "" && isDir(d.root)) || isDir(d.toolsDir)
+}
+
+// Scan reports global tool shims with the package that provides them, the
+// dotnet host, and every installed SDK and runtime with its disk usage
+func (d *DotNet) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	binaries := d.scanTools()
+
+	if d.root == "" {
+		return binaries, nil
+	}
+
+	sdks := readDotnetVersions(filepath.Join(d.root, "sdk"))
+	selected := d.selectedSDK(sdks)
+
+	if host := filepath.Join(d.root, "dotnet"); system.NewFileValidator().IsBinaryExecutable(host) {
+		binaries = append(binaries, &scanner.Binary{
+			Name:    "dotnet",
+			Path:    host,
+			Manager: d.Name(),
+			Version: selected,
+			Package: "dotnet-sdk",
+		})
+	}
+
+	for _, sdk := range sdks {
+		binaries = append(binaries, d.installRecord("dotnet-sdk", filepath.Join(d.root, "sdk", sdk), sdk, sdk != selected, supersededBy(sdk, sdks, sdkFeatureBand)))
+	}
+
+	frameworks, _ := os.ReadDir(filepath.Join(d.root, "shared"))
+	for _, framework := range frameworks {
+		if !framework.IsDir() {
+			continue
+		}
+
+		frameworkDir := filepath.Join(d.root, "shared", framework.Name())
+		versions := readDotnetVersions(frameworkDir)
+		for _, version := range versions {
+			// Runtimes are loaded by apps rather than run from PATH
+			binaries = append(binaries, d.installRecord(framework.Name(), filepath.Join(frameworkDir, version), version, true, supersededBy(version, versions, runtimeBand)))
+		}
+	}
+
+	return binaries, nil
+}
+
+// installRecord reports an SDK or runtime directory with its disk usage
+func (d *DotNet) installRecord(name, path, version string, inactive bool, newer string) *scanner.Binary {
+	binary := &scanner.Binary{
+		Name:     name,
+		Path:     path,
+		Manager:  d.Name(),
+		Version:  version,
+		Package:  name,
+		Inactive: inactive,
+		Size:     system.DirSize(path),
+		Kind:     scanner.KindDirectory,
+	}
+
+	if newer != "" {
+		binary.Notes = append(binary.Notes, fmt.Sprintf("superseded by %s (dotnet-core-uninstall remove %s)", newer, version))
+	}
+	return binary
+}
+
+// dotnetToolSettings is the DotnetToolSettings.xml shipped in each tool package
+type dotnetToolSettings struct {
+	Commands []struct {
+		Name string `xml:"Name,attr"`
+	} `xml:"Commands>Command"`
+}
+
+// scanTools attributes each shim in the global tools directory to its
+// package. Packages are unpacked under .store/<id>/<version>/<id>/<version>,
+// and their DotnetToolSettings.xml names the commands they provide.
+func (d *DotNet) scanTools() []*scanner.Binary {
+	type toolPackage struct {
+		ID      string
+		Version string
+	}
+
+	commands := make(map[string]toolPackage)
+	settingsFiles, _ := filepath.Glob(filepath.Join(d.toolsDir, ".store", "*", "*", "*", "*", "tools", "*", "*", "DotnetToolSettings.xml"))
+	for _, settingsFile := range settingsFiles {
+		data, err := os.ReadFile(settingsFile)
+		if err != nil {
+			continue
+		}
+
+		var settings dotnetToolSettings
+		if err := xml.Unmarshal(data, &settings); err != nil {
+			continue
+		}
+
+		// .store/<id>/<version>/<id>/<version>/tools/<tfm>/<rid>/DotnetToolSettings.xml
+		rel, err := filepath.Rel(filepath.Join(d.toolsDir, ".store"), settingsFile)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(rel, string(filepath.Separator))
+		for _, command := range settings.Commands {
+			commands[command.Name] = toolPackage{ID: parts[0], Version: parts[1]}
+		}
+	}
+
+	var binaries []*scanner.Binary
+	for _, name := range system.NewFileValidator().ListExecutables(d.toolsDir) {
+		pkg, ok := commands[name]
+		if !ok {
+			continue
+		}
+
+		binaries = append(binaries, &scanner.Binary{
+			Name:    name,
+			Path:    filepath.Join(d.toolsDir, name),
+			Manager: d.Name(),
+			Version: pkg.Version,
+			Package: pkg.ID,
+		})
+	}
+
+	return binaries
+}
+
+// selectedSDK returns the SDK the dotnet host uses: the version pinned by
+// the nearest global.json when it's installed, otherwise the newest
+func (d *DotNet) selectedSDK(sdks []string) string {
+	for dir := d.workDir; dir != ""; {
+		if data, err := os.ReadFile(filepath.Join(dir, "global.json")); err == nil {
+			var global struct {
+				SDK struct {
+					Version string `json:"version"`
+				} `json:"sdk"`
+			}
+			if json.Unmarshal(data, &global) == nil {
+				for _, sdk := range sdks {
+					if sdk == global.SDK.Version {
+						return sdk
+					}
+				}
+			}
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return latestVersion(sdks)
+}
+
+// readDotnetVersions lists the version directories of an SDK or runtime
+// directory, in ascending order
+func readDotnetVersions(dir string) []string {
+	var versions []string
+	for _, version := range readVersionDirs(dir, false) {
+		if dotnetVersionPattern.MatchString(version) {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// supersededBy returns the newest version in the same band as version when
+// it's newer, or "" when version is the newest of its band
+func supersededBy(version string, versions []string, band func(string) string) string {
+	newest := version
+	for _, v := range versions {
+		if band(v) == band(version) && compareVersions(v, newest) > 0 {
+			newest = v
+		}
+	}
+	if newest == version {
+		return ""
+	}
+	return newest
+}
+
+// sdkFeatureBand returns an SDK's feature band, e.g. "8.0.1" for 8.0.105.
+// Patches within a band replace each other; different bands can coexist.
+func sdkFeatureBand(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 3 || parts[2] == "" {
+		return version
+	}
+	return parts[0] + "." + parts[1] + "." + parts[2][:1]
+}
+
+// runtimeBand returns a runtime's major.minor version, e.g. "8.0" for 8.0.4
+func runtimeBand(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
@@ -0,0 +1,142 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+// writeDotnetRoot creates a dotnet root with the given SDKs and
+// Microsoft.NETCore.App runtimes
+func writeDotnetRoot(t *testing.T, root string, sdks, runtimes []string) {
+	t.Helper()
+	writeFile(t, filepath.Join(root, "dotnet"), "\x7fELF", 0755)
+	for _, sdk := range sdks {
+		writeFile(t, filepath.Join(root, "sdk", sdk, "dotnet.dll"), "sdk", 0644)
+	}
+	for _, runtime := range runtimes {
+		writeFile(t, filepath.Join(root, "shared", "Microsoft.NETCore.App", runtime, "System.Runtime.dll"), "runtime", 0644)
+	}
+}
+
+// findBinaryVersion returns the binary with the given name and version
+func findBinaryVersion(binaries []*scanner.Binary, name, version string) *scanner.Binary {
+	for _, binary := range binaries {
+		if binary.Name == name && binary.Version == version {
+			return binary
+		}
+	}
+	return nil
+}
+
+func TestDotNetScanTools(t *testing.T) {
+	tools := t.TempDir()
+	writeFile(t, filepath.Join(tools, ".store", "dotnet-ef", "8.0.1", "dotnet-ef", "8.0.1", "tools", "net8.0", "any", "DotnetToolSettings.xml"), `<?xml version="1.0" encoding="utf-8"?>
+<DotNetCliTool Version="1">
+  <Commands>
+    <Command Name="dotnet-ef" EntryPoint="dotnet-ef.dll" Runner="dotnet" />
+  </Commands>
+</DotNetCliTool>
+`, 0644)
+	writeFile(t, filepath.Join(tools, "dotnet-ef"), "\x7fELF", 0755)
+	// A shim without a package in the store isn't the tool manager's
+	writeFile(t, filepath.Join(tools, "stray"), "\x7fELF", 0755)
+
+	dotnet := &DotNet{executor: &fakeExecutor{}, toolsDir: tools}
+	binaries, err := dotnet.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(binaries) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(binaries))
+	}
+	if ef := binaries[0]; ef.Name != "dotnet-ef" || ef.Package != "dotnet-ef" || ef.Version != "8.0.1" || ef.Path != filepath.Join(tools, "dotnet-ef") {
+		t.Errorf("Unexpected tool: %+v", ef)
+	}
+}
+
+func TestDotNetScanSDKsAndRuntimes(t *testing.T) {
+	root := t.TempDir()
+	writeDotnetRoot(t, root, []string{"7.0.400", "8.0.101", "8.0.105", "8.0.204"}, []string{"6.0.25", "8.0.1", "8.0.4"})
+	writeFile(t, filepath.Join(root, "sdk", "NuGetFallbackFolder", "microsoft.netcore.app", "placeholder"), "", 0644)
+
+	dotnet := &DotNet{executor: &fakeExecutor{}, toolsDir: t.TempDir(), root: root, workDir: t.TempDir()}
+	binaries, err := dotnet.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, binary := range binaries {
+		if binary.Version == "NuGetFallbackFolder" {
+			t.Errorf("Expected NuGetFallbackFolder not to be reported as an SDK, got %+v", binary)
+		}
+	}
+
+	host := findBinary(binaries, "dotnet")
+	if host == nil || host.Version != "8.0.204" {
+		t.Errorf("Expected the host to use the newest SDK without a global.json, got %+v", host)
+	}
+
+	// Patches replace each other within an SDK feature band or a runtime's
+	// major.minor; other bands are left alone
+	superseded := map[string]string{
+		"dotnet-sdk 7.0.400":           "",
+		"dotnet-sdk 8.0.101":           "superseded by 8.0.105 (dotnet-core-uninstall remove 8.0.101)",
+		"dotnet-sdk 8.0.105":           "",
+		"dotnet-sdk 8.0.204":           "",
+		"Microsoft.NETCore.App 6.0.25": "",
+		"Microsoft.NETCore.App 8.0.1":  "superseded by 8.0.4 (dotnet-core-uninstall remove 8.0.1)",
+		"Microsoft.NETCore.App 8.0.4":  "",
+	}
+	for key, note := range superseded {
+		name, version, _ := strings.Cut(key, " ")
+		record := findBinaryVersion(binaries, name, version)
+		if record == nil {
+			t.Errorf("Expected a record for %s", key)
+			continue
+		}
+		got := ""
+		if len(record.Notes) > 0 {
+			got = record.Notes[0]
+		}
+		if got != note {
+			t.Errorf("Expected %s to have note %q, got %q", key, note, got)
+		}
+		if name == "dotnet-sdk" && record.Inactive != (version != "8.0.204") {
+			t.Errorf("Expected only the selected SDK to be active, got %s inactive=%v", key, record.Inactive)
+		}
+	}
+}
+
+func TestDotNetGlobalJSONSelection(t *testing.T) {
+	root := t.TempDir()
+	writeDotnetRoot(t, root, []string{"7.0.400", "8.0.105"}, nil)
+
+	project := t.TempDir()
+	writeFile(t, filepath.Join(project, "global.json"), `{"sdk": {"version": "7.0.400", "rollForward": "latestFeature"}}`, 0644)
+	workDir := filepath.Join(project, "src", "App")
+	writeFile(t, filepath.Join(workDir, "App.csproj"), "<Project />", 0644)
+
+	dotnet := &DotNet{executor: &fakeExecutor{}, toolsDir: t.TempDir(), root: root, workDir: workDir}
+	binaries, err := dotnet.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if host := findBinary(binaries, "dotnet"); host == nil || host.Version != "7.0.400" {
+		t.Errorf("Expected the nearest global.json to select 7.0.400, got %+v", host)
+	}
+	if sdk := findBinaryVersion(binaries, "dotnet-sdk", "7.0.400"); sdk == nil || sdk.Inactive {
+		t.Errorf("Expected the pinned SDK to be active, got %+v", sdk)
+	}
+
+	// A pin to an SDK that isn't installed falls back to the newest
+	writeFile(t, filepath.Join(project, "global.json"), `{"sdk": {"version": "6.0.100"}}`, 0644)
+	if selected := dotnet.selectedSDK([]string{"7.0.400", "8.0.105"}); selected != "8.0.105" {
+		t.Errorf("Expected a missing pinned SDK to fall back to 8.0.105, got %s", selected)
+	}
+}
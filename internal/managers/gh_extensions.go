@@ -0,0 +1,97 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// GhExtensions implements the PackageManager interface for GitHub CLI
+// extensions installed with `gh extension install`
+type GhExtensions struct {
+	executor system.CommandExecutor
+	dir      string
+}
+
+// NewGhExtensions creates a new gh extensions package manager
+func NewGhExtensions(executor system.CommandExecutor) *GhExtensions {
+	dataDir := os.Getenv("GH_DATA_DIR")
+	if dataDir == "" {
+		dataDir = xdgDir("", "XDG_DATA_HOME", filepath.Join(".local", "share"), "gh")
+	}
+
+	return &GhExtensions{
+		executor: executor,
+		dir:      filepath.Join(dataDir, "extensions"),
+	}
+}
+
+// Name returns the name of the package manager
+func (g *GhExtensions) Name() string {
+	return "gh"
+}
+
+// IsAvailable checks if gh has an extensions directory
+func (g *GhExtensions) IsAvailable(ctx context.Context) bool {
+	return isDir(g.dir)
+}
+
+// Scan reports each extension in the extensions directory. Binary
+// extensions record their release in manifest.yml; script extensions are
+// git clones, versioned by commit; local extensions are symlinks to a
+// development checkout. Each runs as gh-<name>/gh-<name>.
+func (g *GhExtensions) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	entries, err := os.ReadDir(g.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var binaries []*scanner.Binary
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "gh-") {
+			continue
+		}
+
+		extDir := filepath.Join(g.dir, name)
+		version, pkg, source := readGhExtension(extDir)
+		binaries = append(binaries, pluginBinary(g.Name(), name, filepath.Join(extDir, name), version, pkg, source))
+	}
+
+	return binaries, nil
+}
+
+// readGhExtension returns an extension's version, owner/repo and source URL
+func readGhExtension(extDir string) (string, string, string) {
+	if manifest, err := readYAML(filepath.Join(extDir, "manifest.yml")); err == nil {
+		owner, repo, host := manifest.Values["owner"], manifest.Values["name"], manifest.Values["host"]
+		if host == "" {
+			host = "github.com"
+		}
+
+		version := manifest.Values["tag"]
+		if manifest.Values["ispinned"] == "true" {
+			version += " (pinned)"
+		}
+		return version, owner + "/" + repo, "https://" + host + "/" + owner + "/" + repo
+	}
+
+	dir := gitDir(extDir)
+	if dir == "" {
+		return "", filepath.Base(extDir), ""
+	}
+
+	_, commit := readGitHead(dir)
+	source := readGitRemoteURL(dir, "origin")
+
+	pkg := filepath.Base(extDir)
+	if owner := filepath.Base(filepath.Dir(strings.TrimSuffix(source, ".git"))); source != "" && owner != "." {
+		// Both https://github.com/owner/gh-x and git@github.com:owner/gh-x
+		pkg = owner[strings.LastIndex(owner, ":")+1:] + "/" + pkg
+	}
+	return shortCommit(commit), pkg, source
+}
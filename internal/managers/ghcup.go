@@ -0,0 +1,257 @@
+package managers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// versionedNamePattern matches executables with a version suffix, such as
+// ghc-9.4.8, ghc-pkg-9.4.8 or cabal-3.10.2.0
+var versionedNamePattern = regexp.MustCompile(`^(.+?)-(\d+(?:\.\d+)+)$`)
+
+// numericVersionPattern matches a bare dotted version such as 9.4.8
+var numericVersionPattern = regexp.MustCompile(`^\d+(?:\.\d+)+$`)
+
+// hlsPrefix starts the name of every haskell-language-server executable
+const hlsPrefix = "haskell-language-server-"
+
+// Ghcup implements the PackageManager interface for GHC, cabal, stack and
+// haskell-language-server versions installed with ghcup
+type Ghcup struct {
+	executor system.CommandExecutor
+	dir      string
+}
+
+// ghcupExecutable is what an executable's name and link say about it
+type ghcupExecutable struct {
+	Tool       string // ghc, cabal, stack, hls or ghcup
+	Version    string
+	GHCVersion string // The GHC an HLS binary was built for
+}
+
+// ghcupState is what ghcup records about its installs: the installed
+// versions of each tool and the one set as the default
+type ghcupState struct {
+	Installed map[string][]string
+	Set       map[string]string
+}
+
+// NewGhcup creates a new ghcup package manager
+func NewGhcup(executor system.CommandExecutor) *Ghcup {
+	base := os.Getenv("GHCUP_INSTALL_BASE_PREFIX")
+	if base == "" {
+		base = system.GetHomeDir()
+	}
+
+	return &Ghcup{
+		executor: executor,
+		dir:      filepath.Join(base, ".ghcup"),
+	}
+}
+
+// Name returns the name of the package manager
+func (g *Ghcup) Name() string {
+	return "ghcup"
+}
+
+// IsAvailable checks if ghcup has a bin directory
+func (g *Ghcup) IsAvailable(ctx context.Context) bool {
+	return isDir(filepath.Join(g.dir, "bin"))
+}
+
+// Scan attributes every executable in ghcup's bin directory to the tool and
+// version it belongs to. The installed and set versions come from ghcup's
+// own records; versioned names like ghc-9.4.8 and the links ghcup set
+// points at fill in the rest. HLS binaries built for a GHC that is no
+// longer installed are flagged.
+func (g *Ghcup) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	binDir := filepath.Join(g.dir, "bin")
+	state := g.readState(ctx)
+	installedGHCs := make(map[string]bool)
+	for _, version := range state.Installed["ghc"] {
+		installedGHCs[version] = true
+	}
+
+	var binaries []*scanner.Binary
+	setGHC := state.Set["ghc"]
+	hlsGHCs := make(map[string]bool)
+	var wrapper *scanner.Binary
+
+	for _, name := range system.NewFileValidator().ListExecutables(binDir) {
+		path := filepath.Join(binDir, name)
+		exe := readGhcupExecutable(path, name)
+		if exe.Version == "" && exe.Tool != "ghcup" {
+			exe.Version = state.Set[exe.Tool]
+		}
+
+		binary := &scanner.Binary{
+			Name:    name,
+			Path:    path,
+			Manager: g.Name(),
+			Version: exe.Version,
+			Package: exe.Tool,
+		}
+
+		switch {
+		case exe.Tool == "ghc" && name == "ghc" && setGHC == "":
+			setGHC = exe.Version
+		case exe.Tool == "ghc" && name == "ghc-"+exe.Version:
+			binary.Size = system.DirSize(filepath.Join(g.dir, "ghc", exe.Version))
+		case exe.Tool == "hls" && exe.GHCVersion != "":
+			hlsGHCs[exe.GHCVersion] = true
+			if !installedGHCs[exe.GHCVersion] {
+				binary.Notes = append(binary.Notes, fmt.Sprintf("built for GHC %s, which is not installed (ghcup rm hls %s)", exe.GHCVersion, exe.Version))
+			}
+		case name == "haskell-language-server-wrapper":
+			wrapper = binary
+		}
+
+		binaries = append(binaries, binary)
+	}
+
+	if wrapper != nil && setGHC != "" && len(hlsGHCs) > 0 && !hlsGHCs[setGHC] {
+		wrapper.Notes = append(wrapper.Notes, fmt.Sprintf("no haskell-language-server build for the default GHC %s", setGHC))
+	}
+
+	return binaries, nil
+}
+
+// readState reads ghcup's records of its installs with `ghcup list
+// --raw-format`. Without a working ghcup, the install directories under
+// ghc/ list the installed GHCs, and the set versions are left to the links
+// in bin/.
+func (g *Ghcup) readState(ctx context.Context) ghcupState {
+	if g.executor != nil && g.executor.IsAvailable(ctx, "ghcup") {
+		if output, err := g.executor.Execute(ctx, "ghcup", "list", "--raw-format", "--show-criteria", "installed"); err == nil {
+			if state := parseGhcupList(output); len(state.Installed) > 0 {
+				return state
+			}
+		}
+	}
+
+	return ghcupState{
+		Installed: map[string][]string{"ghc": readVersionDirs(filepath.Join(g.dir, "ghc"), false)},
+		Set:       make(map[string]string),
+	}
+}
+
+// parseGhcupList reads the output of `ghcup list --raw-format`: a line per
+// tool version with its tags, such as "ghc 9.4.8 base-4.17.2.1
+// recommended,installed,set". Older versions start each line with a ✔✔
+// for the set version and a ✓ for other installed ones.
+func parseGhcupList(output string) ghcupState {
+	state := ghcupState{Installed: make(map[string][]string), Set: make(map[string]string)}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		marker := ""
+		if len(fields) > 0 && strings.ContainsAny(fields[0], "✔✓✗") {
+			marker, fields = fields[0], fields[1:]
+		}
+		if len(fields) < 2 {
+			continue
+		}
+
+		tool, version := fields[0], fields[1]
+		installed, set := marker == "✓" || marker == "✔", marker == "✔✔"
+		for _, field := range fields[2:] {
+			for _, tag := range strings.Split(field, ",") {
+				switch tag {
+				case "installed":
+					installed = true
+				case "set":
+					set = true
+				}
+			}
+		}
+
+		if set {
+			state.Set[tool] = version
+			installed = true
+		}
+		if installed {
+			state.Installed[tool] = append(state.Installed[tool], version)
+		}
+	}
+	return state
+}
+
+// readGhcupExecutable works out the tool and version of an executable in
+// ghcup's bin directory. Unversioned names such as ghc are links to a
+// versioned name or into an install directory, so the link is followed once.
+func readGhcupExecutable(path, name string) ghcupExecutable {
+	exe, ok := parseGhcupName(name)
+	if ok {
+		return exe
+	}
+
+	exe.Tool = ghcupTool(name)
+	if name == "ghcup" {
+		return exe
+	}
+
+	target, err := os.Readlink(path)
+	if err != nil {
+		return exe
+	}
+
+	if linked, ok := parseGhcupName(filepath.Base(target)); ok {
+		// haskell-language-server-9.4.8 already named its GHC
+		if exe.GHCVersion == "" {
+			exe.GHCVersion = linked.GHCVersion
+		}
+		exe.Version = linked.Version
+		return exe
+	}
+
+	// Newer ghcup links straight into ghc/<version>/bin or hls/<version>/...
+	parts := strings.Split(filepath.ToSlash(target), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if (parts[i] == "ghc" || parts[i] == "hls") && numericVersionPattern.MatchString(parts[i+1]) {
+			exe.Version = parts[i+1]
+			break
+		}
+	}
+	return exe
+}
+
+// parseGhcupName reads the tool and version from a versioned name, such as
+// ghc-9.4.8 or haskell-language-server-9.4.8~2.5.0.0 (built for GHC 9.4.8).
+// It reports false for names that don't carry a version, though it still
+// returns the GHC named by haskell-language-server-9.4.8.
+func parseGhcupName(name string) (ghcupExecutable, bool) {
+	if rest, ok := strings.CutPrefix(name, hlsPrefix); ok {
+		if ghc, hls, ok := strings.Cut(rest, "~"); ok {
+			return ghcupExecutable{Tool: "hls", Version: hls, GHCVersion: ghc}, true
+		}
+	}
+
+	match := versionedNamePattern.FindStringSubmatch(name)
+	if match == nil {
+		return ghcupExecutable{}, false
+	}
+
+	// haskell-language-server-9.4.8 names the GHC, not the HLS version
+	if match[1]+"-" == hlsPrefix {
+		return ghcupExecutable{Tool: "hls", GHCVersion: match[2]}, false
+	}
+	return ghcupExecutable{Tool: ghcupTool(match[1]), Version: match[2]}, true
+}
+
+// ghcupTool maps an executable name to the ghcup tool that provides it.
+// Everything else in ghcup's bin directory ships with GHC.
+func ghcupTool(name string) string {
+	switch {
+	case name == "cabal", name == "stack", name == "ghcup":
+		return name
+	case strings.HasPrefix(name, "haskell-language-server"):
+		return "hls"
+	}
+	return "ghc"
+}
@@ -0,0 +1,138 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func symlink(t *testing.T, target, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGhcupScan(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin")
+
+	writeFile(t, filepath.Join(dir, "ghc", "9.4.8", "bin", "ghc-9.4.8"), "", 0755)
+	symlink(t, "../ghc/9.4.8/bin/ghc-9.4.8", filepath.Join(bin, "ghc-9.4.8"))
+	symlink(t, "ghc-9.4.8", filepath.Join(bin, "ghc"))
+	writeFile(t, filepath.Join(bin, "cabal-3.10.2.0"), "", 0755)
+	symlink(t, "cabal-3.10.2.0", filepath.Join(bin, "cabal"))
+	writeFile(t, filepath.Join(bin, "ghcup"), "", 0755)
+	writeFile(t, filepath.Join(bin, "haskell-language-server-9.2.8~2.5.0.0"), "", 0755)
+	writeFile(t, filepath.Join(bin, "haskell-language-server-wrapper-2.5.0.0"), "", 0755)
+	symlink(t, "haskell-language-server-wrapper-2.5.0.0", filepath.Join(bin, "haskell-language-server-wrapper"))
+
+	binaries, err := (&Ghcup{dir: dir}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"ghc":                                   "ghc 9.4.8",
+		"ghc-9.4.8":                             "ghc 9.4.8",
+		"cabal":                                 "cabal 3.10.2.0",
+		"cabal-3.10.2.0":                        "cabal 3.10.2.0",
+		"ghcup":                                 "ghcup ",
+		"haskell-language-server-9.2.8~2.5.0.0": "hls 2.5.0.0",
+		"haskell-language-server-wrapper":       "hls 2.5.0.0",
+		"haskell-language-server-wrapper-2.5.0.0": "hls 2.5.0.0",
+	}
+
+	if len(binaries) != len(expected) {
+		t.Errorf("Expected %d binaries, got %d", len(expected), len(binaries))
+	}
+
+	for _, binary := range binaries {
+		if got := binary.Package + " " + binary.Version; got != expected[binary.Name] {
+			t.Errorf("Expected %s to be %q, got %q", binary.Name, expected[binary.Name], got)
+		}
+
+		notes := strings.Join(binary.Notes, "; ")
+		switch binary.Name {
+		case "haskell-language-server-9.2.8~2.5.0.0":
+			if !strings.Contains(notes, "built for GHC 9.2.8, which is not installed") {
+				t.Errorf("Expected orphaned HLS note, got %q", notes)
+			}
+		case "haskell-language-server-wrapper":
+			if !strings.Contains(notes, "no haskell-language-server build for the default GHC 9.4.8") {
+				t.Errorf("Expected wrapper note, got %q", notes)
+			}
+		}
+	}
+}
+
+func TestGhcupScanReadsGhcupList(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin")
+
+	// Copies rather than links, so only ghcup can tell their versions
+	for _, name := range []string{"ghc", "cabal", "stack", "ghcup", "haskell-language-server-9.2.8~2.5.0.0", "haskell-language-server-wrapper"} {
+		writeFile(t, filepath.Join(bin, name), "", 0755)
+	}
+
+	executor := &fakeExecutor{
+		available: map[string]bool{"ghcup": true},
+		outputs: map[string]string{
+			"ghcup list --raw-format --show-criteria installed": `ghc 9.2.8 base-4.16.4.0 hls-powered,installed
+ghc 9.4.8 base-4.17.2.1 recommended,hls-powered,installed,set
+cabal 3.10.2.0 latest,recommended,installed,set
+hls 2.5.0.0 latest,installed,set
+stack 2.13.1 recommended,installed,set
+ghcup 0.1.20.0 latest,recommended,installed,set
+`,
+		},
+	}
+	binaries, err := (&Ghcup{executor: executor, dir: dir}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"ghc":                                   "ghc 9.4.8",
+		"cabal":                                 "cabal 3.10.2.0",
+		"stack":                                 "stack 2.13.1",
+		"ghcup":                                 "ghcup ",
+		"haskell-language-server-9.2.8~2.5.0.0": "hls 2.5.0.0",
+		"haskell-language-server-wrapper":       "hls 2.5.0.0",
+	}
+	for _, binary := range binaries {
+		if got := binary.Package + " " + binary.Version; got != expected[binary.Name] {
+			t.Errorf("Expected %s to be %q, got %q", binary.Name, expected[binary.Name], got)
+		}
+
+		notes := strings.Join(binary.Notes, "; ")
+		switch binary.Name {
+		case "haskell-language-server-9.2.8~2.5.0.0":
+			// ghcup still has GHC 9.2.8, though it has no install directory
+			if notes != "" {
+				t.Errorf("Expected no note for HLS built for an installed GHC, got %q", notes)
+			}
+		case "haskell-language-server-wrapper":
+			if !strings.Contains(notes, "no haskell-language-server build for the default GHC 9.4.8") {
+				t.Errorf("Expected wrapper note, got %q", notes)
+			}
+		}
+	}
+}
+
+func TestParseGhcupList(t *testing.T) {
+	// Older ghcup marks installed versions with ✓ and set ones with ✔✔
+	state := parseGhcupList("✔✔ ghc 9.4.8 base-4.17.2.1 recommended\n✓ ghc 9.2.8 base-4.16.4.0\n✗ ghc 9.8.1 base-4.19.0.0 latest\n✔✔ cabal 3.10.2.0 latest\n")
+
+	if got := state.Installed["ghc"]; len(got) != 2 || got[0] != "9.4.8" || got[1] != "9.2.8" {
+		t.Errorf("Expected GHC 9.4.8 and 9.2.8 to be installed, got %v", got)
+	}
+	if state.Set["ghc"] != "9.4.8" || state.Set["cabal"] != "3.10.2.0" {
+		t.Errorf("Unexpected set versions: %v", state.Set)
+	}
+}
@@ -0,0 +1,156 @@
+package managers
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitDir returns the git directory of a working tree, following the
+// "gitdir:" file that worktrees and submodules use, or "" if dir isn't one
+func gitDir(dir string) string {
+	dotGit := filepath.Join(dir, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return dotGit
+	}
+
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return ""
+	}
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(dir, target)
+	}
+	return target
+}
+
+// gitCommonDir returns the directory holding the refs and config shared by
+// all worktrees, which is the git directory itself outside of worktrees
+func gitCommonDir(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	common := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(gitDir, common)
+	}
+	return common
+}
+
+// readGitHead returns the checked out branch ("" when detached) and commit
+// of a git directory
+func readGitHead(gitDir string) (string, string) {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", ""
+	}
+
+	head := strings.TrimSpace(string(data))
+	ref, ok := strings.CutPrefix(head, "ref: ")
+	if !ok {
+		return "", head
+	}
+	return strings.TrimPrefix(ref, "refs/heads/"), resolveGitRef(gitDir, ref)
+}
+
+// resolveGitRef returns the commit a ref such as refs/heads/main points to,
+// from its loose ref file or packed-refs
+func resolveGitRef(gitDir, ref string) string {
+	for _, dir := range []string{gitDir, gitCommonDir(gitDir)} {
+		if data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+
+		file, err := os.Open(filepath.Join(dir, "packed-refs"))
+		if err != nil {
+			continue
+		}
+		lineScanner := bufio.NewScanner(file)
+		for lineScanner.Scan() {
+			if commit, name, ok := strings.Cut(lineScanner.Text(), " "); ok && name == ref {
+				file.Close()
+				return commit
+			}
+		}
+		file.Close()
+	}
+	return ""
+}
+
+// readGitRemoteURL returns the URL of a remote from a git directory's config
+func readGitRemoteURL(gitDir, remote string) string {
+	return readGitConfig(gitDir, `remote "`+remote+`"`, "url")
+}
+
+// readGitUpstream returns the remote-tracking ref a branch is configured to
+// track, such as refs/remotes/origin/main, or "" when it tracks nothing
+func readGitUpstream(gitDir, branch string) string {
+	section := `branch "` + branch + `"`
+	remote := readGitConfig(gitDir, section, "remote")
+	merge := readGitConfig(gitDir, section, "merge")
+	if remote == "" || merge == "" {
+		return ""
+	}
+	if remote == "." {
+		return merge
+	}
+	return "refs/remotes/" + remote + "/" + strings.TrimPrefix(merge, "refs/heads/")
+}
+
+// readGitConfig returns the value of a key in a section, such as
+// `remote "origin"`, of a git directory's config
+func readGitConfig(gitDir, section, key string) string {
+	file, err := os.Open(filepath.Join(gitCommonDir(gitDir), "config"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	current := ""
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		line := strings.TrimSpace(lineScanner.Text())
+		if strings.HasPrefix(line, "[") {
+			current = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+
+		k, value, ok := strings.Cut(line, "=")
+		if ok && current == section && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// findGitWorkTree returns the working tree containing path and its git
+// directory, looking in path's directory and each of its parents
+func findGitWorkTree(path string) (string, string) {
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if gd := gitDir(dir); gd != "" {
+			return dir, gd
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return "", ""
+		}
+	}
+}
+
+// shortCommit abbreviates a commit hash the way git log --oneline does
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
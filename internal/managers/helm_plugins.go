@@ -0,0 +1,129 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// HelmPlugins implements the PackageManager interface for plugins
+// installed with `helm plugin install`
+type HelmPlugins struct {
+	executor system.CommandExecutor
+	dir      string
+}
+
+// NewHelmPlugins creates a new helm plugins package manager
+func NewHelmPlugins(executor system.CommandExecutor) *HelmPlugins {
+	dir := os.Getenv("HELM_PLUGINS")
+	if dir == "" && system.GetPlatform().IsDarwin() {
+		dir = filepath.Join(system.GetHomeDir(), "Library", "helm", "plugins")
+	}
+	if dir == "" {
+		dir = filepath.Join(xdgDir("HELM_DATA_HOME", "XDG_DATA_HOME", filepath.Join(".local", "share"), "helm"), "plugins")
+	}
+
+	return &HelmPlugins{
+		executor: executor,
+		dir:      dir,
+	}
+}
+
+// Name returns the name of the package manager
+func (h *HelmPlugins) Name() string {
+	return "helm"
+}
+
+// IsAvailable checks if helm has a plugins directory
+func (h *HelmPlugins) IsAvailable(ctx context.Context) bool {
+	return isDir(h.dir)
+}
+
+// Scan reads each plugin's plugin.yaml for its name, version and command.
+// Plugins installed from a repository are links to a clone in helm's
+// cache, whose origin remote is reported as the source.
+func (h *HelmPlugins) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	entries, err := os.ReadDir(h.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var binaries []*scanner.Binary
+	for _, entry := range entries {
+		pluginDir := filepath.Join(h.dir, entry.Name())
+		manifest, err := readYAML(filepath.Join(pluginDir, "plugin.yaml"))
+		if err != nil {
+			continue
+		}
+
+		plugin := manifest.Values["name"]
+		if plugin == "" {
+			plugin = entry.Name()
+		}
+
+		var source string
+		if dir := gitDir(pluginDir); dir != "" {
+			source = readGitRemoteURL(dir, "origin")
+		}
+
+		name := "helm-" + plugin
+		version := manifest.Values["version"]
+
+		command := helmPluginCommand(manifest, pluginDir)
+		if command == "" {
+			// Downloader and hook-only plugins have no command to run, and
+			// others only run programs they don't ship
+			binaries = append(binaries, &scanner.Binary{
+				Name:    name,
+				Path:    pluginDir,
+				Manager: h.Name(),
+				Version: version,
+				Package: plugin,
+				Source:  source,
+				Kind:    scanner.KindDirectory,
+			})
+			continue
+		}
+
+		binaries = append(binaries, pluginBinary(h.Name(), name, command, version, plugin, source))
+	}
+
+	return binaries, nil
+}
+
+// helmPluginCommand returns the executable a plugin ships: the first word
+// of the platformCommand matching this OS and architecture, or of command,
+// that points into the plugin's directory once $HELM_PLUGIN_DIR is
+// expanded. Interpreters such as sh in `sh $HELM_PLUGIN_DIR/run.sh` are
+// skipped so the plugin is recorded by its script, and commands that only
+// run programs from elsewhere return "".
+func helmPluginCommand(manifest *yamlDocument, pluginDir string) string {
+	command := manifest.Values["command"]
+	for _, platform := range manifest.Lists["platformCommand"] {
+		if (platform["os"] == "" || platform["os"] == runtime.GOOS) && (platform["arch"] == "" || platform["arch"] == runtime.GOARCH) {
+			command = platform["command"]
+			break
+		}
+	}
+
+	for _, field := range strings.Fields(command) {
+		if !strings.Contains(field, "HELM_PLUGIN_DIR") {
+			continue
+		}
+		executable := os.Expand(strings.Trim(field, `'"`), func(name string) string {
+			if name == "HELM_PLUGIN_DIR" {
+				return pluginDir
+			}
+			return os.Getenv(name)
+		})
+		if strings.HasPrefix(filepath.Clean(executable), pluginDir+string(filepath.Separator)) {
+			return executable
+		}
+	}
+	return ""
+}
@@ -3,17 +3,47 @@ package managers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/alexcloudstar/snappoint/internal/scanner"
 	"github.com/alexcloudstar/snappoint/pkg/system"
 )
 
+// homebrewPrefixes lists the standard Homebrew install prefixes, checked in
+// order when neither HOMEBREW_PREFIX nor brew itself can tell us
+var homebrewPrefixes = []string{
+	"/opt/homebrew",              // Apple Silicon
+	"/usr/local",                 // Intel macOS
+	"/home/linuxbrew/.linuxbrew", // Linuxbrew
+}
+
 // Homebrew implements the PackageManager interface for Homebrew
 type Homebrew struct {
 	executor system.CommandExecutor
+	prefix   string
+	arch     string
+}
+
+// homebrewKeg is a single installed version of a formula in the Cellar
+type homebrewKeg struct {
+	Formula string
+	Version string
+	Path    string
+	Receipt *homebrewReceipt
+}
+
+// homebrewReceipt holds the fields of a keg's INSTALL_RECEIPT.json we use
+type homebrewReceipt struct {
+	Arch                  string `json:"arch"`
+	InstalledOnRequest    bool   `json:"installed_on_request"`
+	InstalledAsDependency bool   `json:"installed_as_dependency"`
+	Source                struct {
+		Tap string `json:"tap"`
+	} `json:"source"`
 }
 
 // NewHomebrew creates a new Homebrew package manager
@@ -23,6 +53,50 @@ func NewHomebrew(executor system.CommandExecutor) *Homebrew {
 	}
 }
 
+// NewHomebrewAt creates a Homebrew package manager for a specific prefix
+func NewHomebrewAt(executor system.CommandExecutor, prefix string) *Homebrew {
+	return &Homebrew{
+		executor: executor,
+		prefix:   prefix,
+		arch:     homebrewArch(prefix, system.GetPlatform()),
+	}
+}
+
+// NewHomebrews creates one Homebrew package manager per install prefix.
+// Macs migrated from Intel often have both /usr/local and /opt/homebrew, and
+// each is scanned separately so their formulae can be compared. When no
+// prefix has a Cellar, a single instance that detects its prefix is returned.
+func NewHomebrews(executor system.CommandExecutor) []*Homebrew {
+	candidates := []string{os.Getenv("HOMEBREW_PREFIX")}
+	candidates = append(candidates, homebrewPrefixes...)
+	if home := system.GetHomeDir(); home != "" {
+		candidates = append(candidates, filepath.Join(home, ".linuxbrew"))
+	}
+
+	var brews []*Homebrew
+	seen := make(map[string]bool)
+	for _, prefix := range candidates {
+		if prefix == "" || seen[filepath.Clean(prefix)] {
+			continue
+		}
+		seen[filepath.Clean(prefix)] = true
+
+		if info, err := os.Stat(filepath.Join(prefix, "Cellar")); err == nil && info.IsDir() {
+			brews = append(brews, NewHomebrewAt(executor, prefix))
+		}
+	}
+
+	if len(brews) == 0 {
+		return []*Homebrew{NewHomebrew(executor)}
+	}
+	return brews
+}
+
+// Prefix returns the Homebrew prefix this instance scans, detecting it if needed
+func (h *Homebrew) Prefix(ctx context.Context) string {
+	return h.resolvePrefix(ctx)
+}
+
 // Name returns the name of the package manager
 func (h *Homebrew) Name() string {
 	return "homebrew"
@@ -30,42 +104,131 @@ func (h *Homebrew) Name() string {
 
 // IsAvailable checks if Homebrew is installed
 func (h *Homebrew) IsAvailable(ctx context.Context) bool {
-	return h.executor.IsAvailable(ctx, "brew")
+	return h.executor.IsAvailable(ctx, "brew") || h.resolvePrefix(ctx) != ""
 }
 
-// Scan discovers all binaries managed by Homebrew
+// Scan discovers all binaries managed by Homebrew. Kegs are read straight
+// from the Cellar, falling back to a single batched brew query when the
+// Cellar can't be read.
 func (h *Homebrew) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	prefix := h.resolvePrefix(ctx)
+	if prefix == "" {
+		return nil, fmt.Errorf("could not determine the Homebrew prefix")
+	}
+	if h.arch == "" {
+		h.arch = homebrewArch(prefix, system.GetPlatform())
+	}
+
+	kegs, err := readCellar(filepath.Join(prefix, "Cellar"))
+	if err != nil {
+		kegs, err = h.installedKegs(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var binaries []*scanner.Binary
+	for _, formula := range sortedFormulae(kegs) {
+		binaries = append(binaries, h.formulaBinaries(prefix, kegs[formula])...)
+	}
 
-	// Get list of installed packages
-	output, err := h.executor.Execute(ctx, "brew", "list", "--formula")
+	binaries = append(binaries, h.scanCasks(prefix)...)
+
+	return binaries, nil
+}
+
+// resolvePrefix finds the Homebrew prefix from HOMEBREW_PREFIX, then
+// `brew --prefix`, then the first standard prefix that has a Cellar
+func (h *Homebrew) resolvePrefix(ctx context.Context) string {
+	if h.prefix != "" {
+		return h.prefix
+	}
+
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		h.prefix = prefix
+		return h.prefix
+	}
+
+	if h.executor.IsAvailable(ctx, "brew") {
+		if output, err := h.executor.Execute(ctx, "brew", "--prefix"); err == nil {
+			if prefix := strings.TrimSpace(output); prefix != "" {
+				h.prefix = prefix
+				return h.prefix
+			}
+		}
+	}
+
+	candidates := append([]string{}, homebrewPrefixes...)
+	if home := system.GetHomeDir(); home != "" {
+		candidates = append(candidates, filepath.Join(home, ".linuxbrew"))
+	}
+	for _, prefix := range candidates {
+		if info, err := os.Stat(filepath.Join(prefix, "Cellar")); err == nil && info.IsDir() {
+			h.prefix = prefix
+			return h.prefix
+		}
+	}
+
+	return ""
+}
+
+// readCellar lists every keg in a Cellar, keyed by formula name. Each keg's
+// install receipt is read when present.
+func readCellar(cellar string) (map[string][]*homebrewKeg, error) {
+	formulae, err := os.ReadDir(cellar)
 	if err != nil {
 		return nil, err
 	}
 
-	packages := strings.Split(strings.TrimSpace(output), "\n")
-
-	// Get info for each package
-	for _, pkg := range packages {
-		if pkg == "" {
+	kegs := make(map[string][]*homebrewKeg)
+	for _, formula := range formulae {
+		if !formula.IsDir() {
 			continue
 		}
 
-		info, err := h.getPackageInfo(ctx, pkg)
+		versions, err := os.ReadDir(filepath.Join(cellar, formula.Name()))
 		if err != nil {
-			// Skip packages that fail to get info
 			continue
 		}
 
-		binaries = append(binaries, info...)
+		for _, version := range versions {
+			if !version.IsDir() || strings.HasPrefix(version.Name(), ".") {
+				continue
+			}
+
+			kegPath := filepath.Join(cellar, formula.Name(), version.Name())
+			kegs[formula.Name()] = append(kegs[formula.Name()], &homebrewKeg{
+				Formula: formula.Name(),
+				Version: version.Name(),
+				Path:    kegPath,
+				Receipt: readInstallReceipt(kegPath),
+			})
+		}
 	}
 
-	return binaries, nil
+	return kegs, nil
 }
 
-// getPackageInfo retrieves detailed information about a Homebrew package
-func (h *Homebrew) getPackageInfo(ctx context.Context, packageName string) ([]*scanner.Binary, error) {
-	output, err := h.executor.Execute(ctx, "brew", "info", "--json=v2", packageName)
+// readInstallReceipt reads a keg's INSTALL_RECEIPT.json, returning nil if
+// it's missing or unreadable
+func readInstallReceipt(kegPath string) *homebrewReceipt {
+	data, err := os.ReadFile(filepath.Join(kegPath, "INSTALL_RECEIPT.json"))
+	if err != nil {
+		return nil
+	}
+
+	var receipt homebrewReceipt
+	if err := json.Unmarshal(data, &receipt); err != nil {
+		return nil
+	}
+
+	return &receipt
+}
+
+// installedKegs asks the prefix's own brew for every installed formula in
+// one call, so each prefix only reports its own kegs
+func (h *Homebrew) installedKegs(ctx context.Context, prefix string) (map[string][]*homebrewKeg, error) {
+	output, err := h.executor.Execute(ctx, filepath.Join(prefix, "bin", "brew"), "info", "--json=v2", "--installed")
 	if err != nil {
 		return nil, err
 	}
@@ -73,11 +236,12 @@ func (h *Homebrew) getPackageInfo(ctx context.Context, packageName string) ([]*s
 	var info struct {
 		Formulae []struct {
 			Name      string `json:"name"`
-			Version   string `json:"version"`
+			Tap       string `json:"tap"`
 			Installed []struct {
-				Version string `json:"version"`
+				Version            string `json:"version"`
+				InstalledOnRequest bool   `json:"installed_on_request"`
+				InstalledAsDep     bool   `json:"installed_as_dependency"`
 			} `json:"installed"`
-			Linked bool `json:"linked"`
 		} `json:"formulae"`
 	}
 
@@ -85,57 +249,244 @@ func (h *Homebrew) getPackageInfo(ctx context.Context, packageName string) ([]*s
 		return nil, err
 	}
 
+	kegs := make(map[string][]*homebrewKeg)
+	for _, formula := range info.Formulae {
+		for _, installed := range formula.Installed {
+			receipt := &homebrewReceipt{
+				InstalledOnRequest:    installed.InstalledOnRequest,
+				InstalledAsDependency: installed.InstalledAsDep,
+			}
+			receipt.Source.Tap = formula.Tap
+
+			kegs[formula.Name] = append(kegs[formula.Name], &homebrewKeg{
+				Formula: formula.Name,
+				Version: installed.Version,
+				Path:    filepath.Join(prefix, "Cellar", formula.Name, installed.Version),
+				Receipt: receipt,
+			})
+		}
+	}
+
+	return kegs, nil
+}
+
+// formulaBinaries reports every installed keg of a formula. The linked keg's
+// binaries are reported from <prefix>/bin. The current keg of a keg-only or
+// unlinked formula is reported from <prefix>/opt, and is only active when
+// that directory is on PATH. Older kegs are reported as stale, with their
+// disk usage.
+func (h *Homebrew) formulaBinaries(prefix string, kegs []*homebrewKeg) []*scanner.Binary {
+	if len(kegs) == 0 {
+		return nil
+	}
+
+	current := currentKeg(prefix, kegs)
+	linked := linkedKeg(prefix, kegs, current)
+
+	var binaries []*scanner.Binary
+	for _, keg := range kegs {
+		switch keg {
+		case linked:
+			binaries = append(binaries, h.linkedBinaries(prefix, keg)...)
+		case current:
+			binaries = append(binaries, h.unlinkedBinaries(prefix, keg)...)
+		default:
+			binaries = append(binaries, h.staleKeg(keg, current))
+		}
+	}
+
+	return binaries
+}
+
+// linkedBinaries finds the binaries a linked keg exposes in <prefix>/bin
+func (h *Homebrew) linkedBinaries(prefix string, keg *homebrewKeg) []*scanner.Binary {
 	var binaries []*scanner.Binary
 	validator := system.NewFileValidator()
-	prefixes := []string{"/opt/homebrew", "/usr/local"}
+	packageName := keg.packageName()
 
-	for _, formula := range info.Formulae {
-		version := formula.Version
-		if len(formula.Installed) > 0 {
-			version = formula.Installed[0].Version
+	names := validator.ListExecutables(filepath.Join(keg.Path, "bin"))
+	if len(names) == 0 {
+		// Fallback for formulae without a bin directory: check prefix/bin/<formula>
+		names = []string{keg.Formula}
+	}
+
+	for _, name := range names {
+		linkedPath := filepath.Join(prefix, "bin", name)
+		if !validator.IsBinaryExecutable(linkedPath) {
+			continue
 		}
 
-		for _, prefix := range prefixes {
-			// Skip prefixes whose Cellar doesn't exist
-			cellarDir := filepath.Join(prefix, "Cellar")
-			if _, err := os.Stat(cellarDir); err != nil {
-				continue
-			}
+		binaries = append(binaries, &scanner.Binary{
+			Name:    name,
+			Path:    linkedPath,
+			Manager: h.Name(),
+			Version: keg.Version,
+			Package: packageName,
+			Prefix:  prefix,
+			Arch:    h.kegArch(keg),
+		})
+	}
 
-			// Try to find all binaries in the Cellar bin directory
-			cellarBinDir := filepath.Join(cellarDir, formula.Name, version, "bin")
-			entries, err := os.ReadDir(cellarBinDir)
-			if err == nil && len(entries) > 0 {
-				for _, entry := range entries {
-					if entry.IsDir() {
-						continue
-					}
-					linkedPath := filepath.Join(prefix, "bin", entry.Name())
-					if validator.IsBinaryExecutable(linkedPath) {
-						binaries = append(binaries, &scanner.Binary{
-							Name:    entry.Name(),
-							Path:    linkedPath,
-							Manager: h.Name(),
-							Version: version,
-							Package: packageName,
-						})
-					}
-				}
-			} else {
-				// Fallback for library-only formulae: check prefix/bin/<formula>
-				binaryPath := filepath.Join(prefix, "bin", formula.Name)
-				if validator.IsBinaryExecutable(binaryPath) {
-					binaries = append(binaries, &scanner.Binary{
-						Name:    formula.Name,
-						Path:    binaryPath,
-						Manager: h.Name(),
-						Version: version,
-						Package: packageName,
-					})
-				}
-			}
+	return binaries
+}
+
+// unlinkedBinaries reports the binaries of a keg that isn't linked into
+// <prefix>/bin, either because the formula is keg-only or because it was
+// unlinked
+func (h *Homebrew) unlinkedBinaries(prefix string, keg *homebrewKeg) []*scanner.Binary {
+	note := "unlinked keg"
+	if keg.isKegOnly() {
+		note = "keg-only"
+	}
+
+	binDir := filepath.Join(prefix, "opt", keg.Formula, "bin")
+	inPath := system.IsInPATH(binDir)
+
+	var binaries []*scanner.Binary
+	for _, name := range system.NewFileValidator().ListExecutables(binDir) {
+		binaries = append(binaries, &scanner.Binary{
+			Name:     name,
+			Path:     filepath.Join(binDir, name),
+			Manager:  h.Name(),
+			Version:  keg.Version,
+			Package:  keg.packageName(),
+			Prefix:   prefix,
+			Arch:     h.kegArch(keg),
+			Inactive: !inPath,
+			Notes:    []string{note},
+		})
+	}
+
+	return binaries
+}
+
+// staleKeg reports an old keg left in the Cellar after an upgrade
+func (h *Homebrew) staleKeg(keg, current *homebrewKeg) *scanner.Binary {
+	return &scanner.Binary{
+		Name:     keg.Formula,
+		Path:     keg.Path,
+		Manager:  h.Name(),
+		Version:  keg.Version,
+		Package:  keg.packageName(),
+		Prefix:   h.prefix,
+		Arch:     h.kegArch(keg),
+		Inactive: true,
+		Size:     system.DirSize(keg.Path),
+		Kind:     scanner.KindDirectory,
+		Notes:    []string{fmt.Sprintf("stale version, %s is current (brew cleanup %s)", current.Version, keg.Formula)},
+	}
+}
+
+// currentKeg returns the keg <prefix>/opt/<formula> points at, or the newest
+// keg when the opt link is missing
+func currentKeg(prefix string, kegs []*homebrewKeg) *homebrewKeg {
+	if keg := kegAtLink(filepath.Join(prefix, "opt", kegs[0].Formula), kegs); keg != nil {
+		return keg
+	}
+
+	newest := kegs[0]
+	for _, keg := range kegs[1:] {
+		if compareVersions(keg.Version, newest.Version) > 0 {
+			newest = keg
 		}
 	}
+	return newest
+}
 
-	return binaries, nil
+// linkedKeg returns the keg Homebrew has linked into the prefix, as recorded
+// in var/homebrew/linked, or nil when the formula isn't linked. Prefixes
+// without that record predate it, so the current keg is assumed linked.
+func linkedKeg(prefix string, kegs []*homebrewKeg, current *homebrewKeg) *homebrewKeg {
+	linkedDir := filepath.Join(prefix, "var", "homebrew", "linked")
+	if _, err := os.Stat(linkedDir); err != nil {
+		return current
+	}
+	return kegAtLink(filepath.Join(linkedDir, kegs[0].Formula), kegs)
+}
+
+// kegAtLink returns the keg a symlink resolves to, or nil if it resolves
+// to none of them
+func kegAtLink(link string, kegs []*homebrewKeg) *homebrewKeg {
+	target, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		return nil
+	}
+
+	for _, keg := range kegs {
+		if resolved, err := filepath.EvalSymlinks(keg.Path); err == nil && resolved == target {
+			return keg
+		}
+	}
+	return nil
+}
+
+// kegArch returns the architecture a keg was built for, as recorded in its
+// install receipt, defaulting to the prefix's architecture
+func (h *Homebrew) kegArch(keg *homebrewKeg) string {
+	if keg.Receipt != nil && keg.Receipt.Arch != "" {
+		return normalizeArch(keg.Receipt.Arch)
+	}
+	return h.arch
+}
+
+// homebrewArch returns the architecture a Homebrew prefix serves. On macOS
+// /opt/homebrew is the Apple Silicon prefix and /usr/local the Intel (or
+// Rosetta) prefix; anywhere else the host architecture is assumed.
+func homebrewArch(prefix string, platform system.Platform) string {
+	if platform.IsDarwin() {
+		switch filepath.Clean(prefix) {
+		case "/opt/homebrew":
+			return "arm64"
+		case "/usr/local":
+			return "amd64"
+		}
+	}
+	return platform.Arch
+}
+
+// normalizeArch converts architecture names such as x86_64 and aarch64 to
+// their Go equivalents
+func normalizeArch(arch string) string {
+	switch arch {
+	case "x86_64", "intel":
+		return "amd64"
+	case "aarch64", "arm":
+		return "arm64"
+	}
+	return arch
+}
+
+// isKegOnly checks the formula definition Homebrew copies into each keg's
+// .brew directory for a keg_only stanza
+func (k *homebrewKeg) isKegOnly() bool {
+	data, err := os.ReadFile(filepath.Join(k.Path, ".brew", k.Formula+".rb"))
+	if err != nil {
+		return false
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "keg_only") {
+			return true
+		}
+	}
+	return false
+}
+
+// packageName returns the formula name, qualified with its tap for formulae
+// that don't come from homebrew/core
+func (k *homebrewKeg) packageName() string {
+	if k.Receipt == nil || k.Receipt.Source.Tap == "" || k.Receipt.Source.Tap == "homebrew/core" {
+		return k.Formula
+	}
+	return k.Receipt.Source.Tap + "/" + k.Formula
+}
+
+// sortedFormulae returns formula names in a stable order
+func sortedFormulae(kegs map[string][]*homebrewKeg) []string {
+	names := make([]string, 0, len(kegs))
+	for name := range kegs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
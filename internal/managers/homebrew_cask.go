@@ -0,0 +1,198 @@
+package managers
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// caskBinaryPattern matches `binary "<source>"` stanzas in a cask definition,
+// with an optional `target: "<name>"`
+var caskBinaryPattern = regexp.MustCompile(`^\s*binary\s+"([^"]+)"(?:\s*,\s*target:\s*"([^"]+)")?`)
+
+// homebrewCaskNote marks binaries installed by a cask rather than a formula
+const homebrewCaskNote = "cask"
+
+// isHomebrewCask reports whether a Homebrew binary was installed by a cask
+func isHomebrewCask(binary *scanner.Binary) bool {
+	for _, note := range binary.Notes {
+		if note == homebrewCaskNote {
+			return true
+		}
+	}
+	return false
+}
+
+// scanCasks discovers binaries installed by casks under a Homebrew prefix.
+// Each cask's install metadata is read from the Caskroom to find its binary
+// artifacts, which Homebrew links into <prefix>/bin.
+func (h *Homebrew) scanCasks(prefix string) []*scanner.Binary {
+	caskroom := filepath.Join(prefix, "Caskroom")
+	casks, err := os.ReadDir(caskroom)
+	if err != nil {
+		return nil
+	}
+
+	var binaries []*scanner.Binary
+	validator := system.NewFileValidator()
+
+	for _, cask := range casks {
+		if !cask.IsDir() || strings.HasPrefix(cask.Name(), ".") {
+			continue
+		}
+
+		caskDir := filepath.Join(caskroom, cask.Name())
+		version := installedCaskVersion(caskDir)
+		if version == "" {
+			continue
+		}
+
+		for _, target := range caskBinaryTargets(caskDir, cask.Name(), version) {
+			linkedPath := filepath.Join(prefix, "bin", target)
+			if !validator.IsBinaryExecutable(linkedPath) {
+				continue
+			}
+
+			binaries = append(binaries, &scanner.Binary{
+				Name:    target,
+				Path:    linkedPath,
+				Manager: h.Name(),
+				Version: version,
+				Package: cask.Name(),
+				Prefix:  prefix,
+				Arch:    h.arch,
+				Notes:   []string{homebrewCaskNote},
+			})
+		}
+	}
+
+	return binaries
+}
+
+// installedCaskVersion returns the installed version of a cask, which is the
+// name of its version directory in the Caskroom
+func installedCaskVersion(caskDir string) string {
+	entries, err := os.ReadDir(caskDir)
+	if err != nil {
+		return ""
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			versions = append(versions, entry.Name())
+		}
+	}
+
+	return latestVersion(versions)
+}
+
+// caskBinaryTargets returns the names of the binaries a cask links into the
+// prefix. The newest install metadata under
+// .metadata/<version>/<timestamp>/Casks is used, in either JSON or Ruby form.
+func caskBinaryTargets(caskDir, token, version string) []string {
+	timestamps, err := os.ReadDir(filepath.Join(caskDir, ".metadata", version))
+	if err != nil {
+		return nil
+	}
+
+	// Timestamps sort lexically, so the last entry is the latest install
+	names := make([]string, 0, len(timestamps))
+	for _, ts := range timestamps {
+		names = append(names, ts.Name())
+	}
+	sort.Strings(names)
+
+	for i := len(names) - 1; i >= 0; i-- {
+		casksDir := filepath.Join(caskDir, ".metadata", version, names[i], "Casks")
+		if targets, ok := parseCaskJSONBinaries(filepath.Join(casksDir, token+".json")); ok {
+			return targets
+		}
+		if targets, ok := parseCaskRubyBinaries(filepath.Join(casksDir, token+".rb")); ok {
+			return targets
+		}
+	}
+
+	return nil
+}
+
+// parseCaskJSONBinaries reads binary artifacts from a cask's JSON metadata.
+// Each artifact is an object such as {"binary": ["<source>", {"target": "<name>"}]}.
+func parseCaskJSONBinaries(path string) ([]string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var cask struct {
+		Artifacts []map[string]json.RawMessage `json:"artifacts"`
+	}
+	if err := json.Unmarshal(data, &cask); err != nil {
+		return nil, false
+	}
+
+	var targets []string
+	for _, artifact := range cask.Artifacts {
+		raw, ok := artifact["binary"]
+		if !ok {
+			continue
+		}
+
+		var args []json.RawMessage
+		if err := json.Unmarshal(raw, &args); err != nil || len(args) == 0 {
+			continue
+		}
+
+		var source string
+		if err := json.Unmarshal(args[0], &source); err != nil {
+			continue
+		}
+
+		target := filepath.Base(source)
+		if len(args) > 1 {
+			var options struct {
+				Target string `json:"target"`
+			}
+			if err := json.Unmarshal(args[1], &options); err == nil && options.Target != "" {
+				target = options.Target
+			}
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets, true
+}
+
+// parseCaskRubyBinaries reads binary stanzas from a cask's Ruby definition
+func parseCaskRubyBinaries(path string) ([]string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	var targets []string
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		match := caskBinaryPattern.FindStringSubmatch(lineScanner.Text())
+		if match == nil {
+			continue
+		}
+
+		if match[2] != "" {
+			targets = append(targets, match[2])
+		} else {
+			targets = append(targets, filepath.Base(match[1]))
+		}
+	}
+
+	return targets, true
+}
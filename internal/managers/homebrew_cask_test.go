@@ -0,0 +1,95 @@
+package managers
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// writeCask creates a Caskroom version directory with install metadata in
+// the given file and links each binary into <prefix>/bin
+func writeCask(t *testing.T, prefix, token, version, timestamp, metadataFile, metadata string, binaries ...string) {
+	t.Helper()
+	caskDir := filepath.Join(prefix, "Caskroom", token)
+	writeFile(t, filepath.Join(caskDir, version, ".keep"), "", 0644)
+	writeFile(t, filepath.Join(caskDir, ".metadata", version, timestamp, "Casks", metadataFile), metadata, 0644)
+	for _, name := range binaries {
+		writeFile(t, filepath.Join(prefix, "bin", name), "#!/bin/sh\n", 0755)
+	}
+}
+
+func TestHomebrewScanCasks(t *testing.T) {
+	prefix := t.TempDir()
+
+	writeCask(t, prefix, "visual-studio-code", "1.87.0", "20240301120000.000", "visual-studio-code.json", `{
+  "token": "visual-studio-code",
+  "artifacts": [
+    {"app": ["Visual Studio Code.app"]},
+    {"binary": ["$APPDIR/Visual Studio Code.app/Contents/Resources/app/bin/code"]},
+    {"zap": [{"trash": ["~/.vscode"]}]}
+  ]
+}`, "code")
+
+	writeCask(t, prefix, "docker", "4.28.0", "20240310090000.000", "docker.json", `{
+  "artifacts": [
+    {"binary": ["$APPDIR/Docker.app/Contents/Resources/bin/docker"]},
+    {"binary": ["$APPDIR/Docker.app/Contents/Resources/bin/hub-tool", {"target": "hub"}]}
+  ]
+}`, "docker", "hub")
+
+	writeCask(t, prefix, "1password-cli", "2.25.0", "20240105080000.000", "1password-cli.rb", `cask "1password-cli" do
+  version "2.25.0"
+
+  binary "op"
+  binary "#{appdir}/1Password.app/Contents/MacOS/op-ssh-sign", target: "op-ssh-sign-helper"
+  binary "missing-link"
+end
+`, "op", "op-ssh-sign-helper")
+	// Metadata from an earlier install of the same version is ignored
+	writeFile(t, filepath.Join(prefix, "Caskroom", "1password-cli", ".metadata", "2.25.0", "20231201080000.000", "Casks", "1password-cli.rb"), "cask \"1password-cli\" do\n  binary \"old-op\"\nend\n", 0644)
+
+	brew := &Homebrew{executor: &fakeExecutor{}, prefix: prefix, arch: "arm64"}
+	binaries := brew.scanCasks(prefix)
+
+	expected := map[string]string{
+		"code":               "visual-studio-code 1.87.0",
+		"docker":             "docker 4.28.0",
+		"hub":                "docker 4.28.0",
+		"op":                 "1password-cli 2.25.0",
+		"op-ssh-sign-helper": "1password-cli 2.25.0",
+	}
+	if len(binaries) != len(expected) {
+		t.Errorf("Expected %d cask binaries, got %v", len(expected), binaries)
+	}
+	for name, pkg := range expected {
+		binary := findBinary(binaries, name)
+		if binary == nil {
+			t.Errorf("Expected %s to be reported", name)
+			continue
+		}
+		if got := binary.Package + " " + binary.Version; got != pkg {
+			t.Errorf("Expected %s to come from %s, got %s", name, pkg, got)
+		}
+		if binary.Path != filepath.Join(prefix, "bin", name) || binary.Prefix != prefix || binary.Arch != "arm64" {
+			t.Errorf("Unexpected %s binary: %+v", name, binary)
+		}
+	}
+}
+
+func TestParseCaskRubyBinaries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool.rb")
+	writeFile(t, path, `cask "tool" do
+  binary "#{staged_path}/tool-#{version}/bin/tool"
+  binary "bin/helper", target: "tool-helper"
+  # binary "commented"
+end
+`, 0644)
+
+	targets, ok := parseCaskRubyBinaries(path)
+	if !ok || len(targets) != 2 || targets[0] != "tool" || targets[1] != "tool-helper" {
+		t.Errorf("Expected [tool tool-helper], got %v", targets)
+	}
+
+	if _, ok := parseCaskJSONBinaries(filepath.Join(t.TempDir(), "missing.json")); ok {
+		t.Error("Expected missing JSON metadata not to parse")
+	}
+}
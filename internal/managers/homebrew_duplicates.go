@@ -0,0 +1,122 @@
+package managers
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// HomebrewDuplicates reports formulae and casks installed in more than one
+// Homebrew prefix, such as both the Intel /usr/local and the Apple Silicon
+// /opt/homebrew on a migrated Mac, and recommends which prefix to keep
+type HomebrewDuplicates struct {
+	hostArch string
+	path     []string
+}
+
+// homebrewInstall identifies a formula or a cask, which may share a name
+type homebrewInstall struct {
+	Package string
+	Cask    bool
+}
+
+// NewHomebrewDuplicates creates a duplicate detector for the current host
+func NewHomebrewDuplicates() *HomebrewDuplicates {
+	return &HomebrewDuplicates{
+		hostArch: system.GetPlatform().Arch,
+		path:     system.GetPATH(),
+	}
+}
+
+// Analyze annotates one binary per duplicated package and prefix with the
+// other prefixes it's installed in and the recommended action
+func (d *HomebrewDuplicates) Analyze(result *scanner.ScanResult) {
+	// Formula or cask -> prefix -> representative binary
+	installs := make(map[homebrewInstall]map[string]*scanner.Binary)
+	for _, binary := range result.Binaries {
+		if binary.Manager != "homebrew" || binary.Prefix == "" || binary.Package == "" {
+			continue
+		}
+
+		install := homebrewInstall{Package: binary.Package, Cask: isHomebrewCask(binary)}
+		byPrefix, ok := installs[install]
+		if !ok {
+			byPrefix = make(map[string]*scanner.Binary)
+			installs[install] = byPrefix
+		}
+
+		// Prefer an active binary to carry the note
+		if existing, ok := byPrefix[binary.Prefix]; !ok || (existing.Inactive && !binary.Inactive) {
+			byPrefix[binary.Prefix] = binary
+		}
+	}
+
+	for install, byPrefix := range installs {
+		if len(byPrefix) < 2 {
+			continue
+		}
+
+		prefixes := make([]string, 0, len(byPrefix))
+		for prefix := range byPrefix {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Strings(prefixes)
+
+		keep := d.preferredPrefix(prefixes, byPrefix)
+		kept := byPrefix[keep]
+
+		// The architecture is left out when it couldn't be determined
+		keptInstall, keptAt := "this", keep
+		if kept.Arch != "" {
+			keptInstall = "this " + kept.Arch
+			keptAt = fmt.Sprintf("%s (%s)", keep, kept.Arch)
+		}
+
+		uninstall := "uninstall " + install.Package
+		if install.Cask {
+			uninstall = "uninstall --cask " + install.Package
+		}
+
+		for _, prefix := range prefixes {
+			binary := byPrefix[prefix]
+			if prefix == keep {
+				binary.Notes = append(binary.Notes, fmt.Sprintf("also installed in %d other Homebrew prefix(es); keep %s install", len(prefixes)-1, keptInstall))
+				continue
+			}
+
+			binary.Notes = append(binary.Notes, fmt.Sprintf("duplicate of %s in %s; remove with %s %s",
+				install.Package, keptAt, filepath.Join(prefix, "bin", "brew"), uninstall))
+		}
+	}
+}
+
+// preferredPrefix picks the prefix to keep: the one native to the host
+// architecture, then whichever comes first on PATH
+func (d *HomebrewDuplicates) preferredPrefix(prefixes []string, byPrefix map[string]*scanner.Binary) string {
+	var native []string
+	for _, prefix := range prefixes {
+		if byPrefix[prefix].Arch == d.hostArch {
+			native = append(native, prefix)
+		}
+	}
+	if len(native) == 1 {
+		return native[0]
+	}
+	if len(native) > 1 {
+		prefixes = native
+	}
+
+	best, bestIndex := prefixes[0], len(d.path)
+	for _, prefix := range prefixes {
+		binDir := filepath.Join(prefix, "bin")
+		for i, dir := range d.path {
+			if filepath.Clean(dir) == binDir && i < bestIndex {
+				best, bestIndex = prefix, i
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,306 @@
+package managers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+// fakeExecutor returns canned output for commands, keyed by the full command line
+type fakeExecutor struct {
+	outputs   map[string]string
+	available map[string]bool
+	calls     []string
+}
+
+func (f *fakeExecutor) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	command := strings.Join(append([]string{name}, args...), " ")
+	f.calls = append(f.calls, command)
+	if output, ok := f.outputs[command]; ok {
+		return output, nil
+	}
+	return "", fmt.Errorf("unexpected command: %s", command)
+}
+
+func (f *fakeExecutor) IsAvailable(ctx context.Context, command string) bool {
+	return f.available[command]
+}
+
+// writeKeg creates a Cellar keg with the given executables and links them
+// into <prefix>/bin and <prefix>/opt when linked is true
+func writeKeg(t *testing.T, prefix, formula, version string, linked bool, executables ...string) string {
+	t.Helper()
+	kegPath := filepath.Join(prefix, "Cellar", formula, version)
+	writeFile(t, filepath.Join(kegPath, "INSTALL_RECEIPT.json"), `{"installed_on_request": true, "source": {"tap": "homebrew/core"}}`, 0644)
+
+	for _, name := range executables {
+		writeFile(t, filepath.Join(kegPath, "bin", name), "", 0755)
+		if !linked {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(prefix, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.Join(kegPath, "bin", name), filepath.Join(prefix, "bin", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if linked {
+		for _, dir := range []string{"opt", filepath.Join("var", "homebrew", "linked")} {
+			if err := os.MkdirAll(filepath.Join(prefix, dir), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Symlink(kegPath, filepath.Join(prefix, dir, formula)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return kegPath
+}
+
+func TestHomebrewScanReadsCellar(t *testing.T) {
+	prefix := t.TempDir()
+	writeKeg(t, prefix, "ripgrep", "14.1.0", true, "rg")
+	writeKeg(t, prefix, "jq", "1.7.1", true, "jq")
+
+	executor := &fakeExecutor{}
+	brew := &Homebrew{executor: executor, prefix: prefix}
+
+	binaries, err := brew.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(executor.calls) != 0 {
+		t.Errorf("Expected no brew commands, got %v", executor.calls)
+	}
+
+	found := make(map[string]string)
+	for _, binary := range binaries {
+		found[binary.Name] = binary.Version
+	}
+
+	if found["rg"] != "14.1.0" || found["jq"] != "1.7.1" {
+		t.Errorf("Unexpected binaries: %v", found)
+	}
+}
+
+func TestHomebrewScanFallsBackToBrewInfo(t *testing.T) {
+	armPrefix := t.TempDir()
+	intelPrefix := t.TempDir()
+	writeFile(t, filepath.Join(armPrefix, "bin", "wget"), "", 0755)
+	writeFile(t, filepath.Join(intelPrefix, "bin", "wget"), "", 0755)
+	writeFile(t, filepath.Join(intelPrefix, "bin", "jq"), "", 0755)
+
+	// Each prefix's brew only knows about its own kegs
+	executor := &fakeExecutor{
+		outputs: map[string]string{
+			filepath.Join(armPrefix, "bin", "brew") + " info --json=v2 --installed":   `{"formulae": [{"name": "wget", "tap": "homebrew/core", "installed": [{"version": "1.24.5"}]}], "casks": []}`,
+			filepath.Join(intelPrefix, "bin", "brew") + " info --json=v2 --installed": `{"formulae": [{"name": "wget", "tap": "homebrew/core", "installed": [{"version": "1.21.4"}]}, {"name": "jq", "tap": "homebrew/core", "installed": [{"version": "1.6"}]}], "casks": []}`,
+		},
+	}
+
+	expected := map[string]string{
+		armPrefix:   "wget 1.24.5",
+		intelPrefix: "jq 1.6, wget 1.21.4",
+	}
+	for _, prefix := range []string{armPrefix, intelPrefix} {
+		brew := &Homebrew{executor: executor, prefix: prefix}
+		binaries, err := brew.Scan(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, binary := range binaries {
+			if binary.Prefix != prefix {
+				t.Errorf("Expected %s to be attributed to %s, got %s", binary.Name, prefix, binary.Prefix)
+			}
+			got = append(got, binary.Name+" "+binary.Version)
+		}
+		if strings.Join(got, ", ") != expected[prefix] {
+			t.Errorf("Expected %s to report %q, got %q", prefix, expected[prefix], strings.Join(got, ", "))
+		}
+	}
+}
+
+func TestHomebrewResolvePrefix(t *testing.T) {
+	t.Setenv("HOMEBREW_PREFIX", "")
+
+	executor := &fakeExecutor{
+		outputs:   map[string]string{"brew --prefix": "/home/linuxbrew/.linuxbrew\n"},
+		available: map[string]bool{"brew": true},
+	}
+	brew := NewHomebrew(executor)
+
+	if prefix := brew.resolvePrefix(context.Background()); prefix != "/home/linuxbrew/.linuxbrew" {
+		t.Errorf("Expected Linuxbrew prefix, got %q", prefix)
+	}
+
+	t.Setenv("HOMEBREW_PREFIX", "/custom/brew")
+	brew = NewHomebrew(executor)
+	if prefix := brew.resolvePrefix(context.Background()); prefix != "/custom/brew" {
+		t.Errorf("Expected HOMEBREW_PREFIX to win, got %q", prefix)
+	}
+}
+
+func TestHomebrewScanReportsKegs(t *testing.T) {
+	prefix := t.TempDir()
+	t.Setenv("PATH", "")
+
+	stale := writeKeg(t, prefix, "jq", "1.6", false, "jq")
+	writeKeg(t, prefix, "jq", "1.7.1", true, "jq")
+
+	kegOnly := writeKeg(t, prefix, "openssl@3", "3.2.1", false, "openssl")
+	writeFile(t, filepath.Join(kegOnly, ".brew", "openssl@3.rb"), "class OpensslAT3 < Formula\n  keg_only :provided_by_macos\nend\n", 0644)
+	if err := os.Symlink(kegOnly, filepath.Join(prefix, "opt", "openssl@3")); err != nil {
+		t.Fatal(err)
+	}
+
+	brew := &Homebrew{executor: &fakeExecutor{}, prefix: prefix}
+	binaries, err := brew.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(binaries) != 3 {
+		t.Fatalf("Expected 3 binaries, got %d: %v", len(binaries), binaries)
+	}
+
+	for _, binary := range binaries {
+		switch binary.Path {
+		case filepath.Join(prefix, "bin", "jq"):
+			if binary.Version != "1.7.1" || binary.Inactive {
+				t.Errorf("Unexpected linked binary: %s", binary)
+			}
+		case stale:
+			if !binary.Inactive || binary.Kind != scanner.KindDirectory || binary.Size == 0 || len(binary.Notes) != 1 {
+				t.Errorf("Expected stale keg with size and note: %s", binary)
+			}
+		case filepath.Join(prefix, "opt", "openssl@3", "bin", "openssl"):
+			if !binary.Inactive || len(binary.Notes) != 1 || binary.Notes[0] != "keg-only" {
+				t.Errorf("Expected inactive keg-only binary: %s %v", binary, binary.Notes)
+			}
+		default:
+			t.Errorf("Unexpected binary: %s", binary)
+		}
+	}
+}
+
+func TestHomebrewDuplicatesAcrossPrefixes(t *testing.T) {
+	armPrefix := t.TempDir()
+	intelPrefix := t.TempDir()
+	writeKeg(t, armPrefix, "jq", "1.7.1", true, "jq")
+	writeKeg(t, intelPrefix, "jq", "1.6", true, "jq")
+	writeKeg(t, intelPrefix, "wget", "1.24.5", true, "wget")
+
+	result := scanner.NewScanResult()
+	for _, brew := range []*Homebrew{
+		{executor: &fakeExecutor{}, prefix: armPrefix, arch: "arm64"},
+		{executor: &fakeExecutor{}, prefix: intelPrefix, arch: "amd64"},
+	} {
+		binaries, err := brew.Scan(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, binary := range binaries {
+			result.AddBinary(binary)
+		}
+	}
+	result.DetectConflicts()
+
+	if result.ConflictCount() != 1 {
+		t.Errorf("Expected jq to conflict across prefixes, got %d conflicts", result.ConflictCount())
+	}
+
+	analyzer := &HomebrewDuplicates{hostArch: "arm64"}
+	result.Analyze(analyzer)
+
+	for _, binary := range result.Binaries {
+		switch {
+		case binary.Name == "wget":
+			if len(binary.Notes) != 0 {
+				t.Errorf("Expected no notes for wget, got %v", binary.Notes)
+			}
+		case binary.Prefix == armPrefix:
+			if binary.Arch != "arm64" || len(binary.Notes) != 1 || !strings.Contains(binary.Notes[0], "keep this") {
+				t.Errorf("Expected arm64 jq to be kept, got %s %v", binary.Arch, binary.Notes)
+			}
+		case binary.Prefix == intelPrefix:
+			if binary.Arch != "amd64" || len(binary.Notes) != 1 || !strings.Contains(binary.Notes[0], "uninstall jq") {
+				t.Errorf("Expected amd64 jq to be flagged for removal, got %s %v", binary.Arch, binary.Notes)
+			}
+		}
+	}
+}
+
+func TestHomebrewDuplicatesUnknownArch(t *testing.T) {
+	firstPrefix := t.TempDir()
+	secondPrefix := t.TempDir()
+
+	result := scanner.NewScanResult()
+	result.AddBinary(&scanner.Binary{Name: "jq", Path: filepath.Join(firstPrefix, "bin", "jq"), Manager: "homebrew", Package: "jq", Prefix: firstPrefix})
+	result.AddBinary(&scanner.Binary{Name: "jq", Path: filepath.Join(secondPrefix, "bin", "jq"), Manager: "homebrew", Package: "jq", Prefix: secondPrefix})
+
+	analyzer := &HomebrewDuplicates{hostArch: "arm64", path: []string{filepath.Join(firstPrefix, "bin")}}
+	result.Analyze(analyzer)
+
+	expected := map[string]string{
+		firstPrefix:  "also installed in 1 other Homebrew prefix(es); keep this install",
+		secondPrefix: "duplicate of jq in " + firstPrefix + "; remove with " + filepath.Join(secondPrefix, "bin", "brew") + " uninstall jq",
+	}
+	for _, binary := range result.Binaries {
+		if len(binary.Notes) != 1 || binary.Notes[0] != expected[binary.Prefix] {
+			t.Errorf("Expected note %q for %s, got %v", expected[binary.Prefix], binary.Prefix, binary.Notes)
+		}
+	}
+}
+
+func TestHomebrewDuplicatesCasks(t *testing.T) {
+	armPrefix := t.TempDir()
+	intelPrefix := t.TempDir()
+
+	result := scanner.NewScanResult()
+	for _, brew := range []*Homebrew{
+		{executor: &fakeExecutor{}, prefix: armPrefix, arch: "arm64"},
+		{executor: &fakeExecutor{}, prefix: intelPrefix, arch: "amd64"},
+	} {
+		writeCask(t, brew.prefix, "docker", "4.28.0", "20240310090000.000", "docker.json", `{"artifacts": [{"binary": ["$APPDIR/Docker.app/Contents/Resources/bin/docker"]}]}`, "docker")
+		for _, binary := range brew.scanCasks(brew.prefix) {
+			result.AddBinary(binary)
+		}
+	}
+	// A formula of the same name is a different install
+	result.AddBinary(&scanner.Binary{Name: "docker", Path: filepath.Join(intelPrefix, "opt", "docker", "bin", "docker"), Manager: "homebrew", Package: "docker", Prefix: intelPrefix, Arch: "amd64"})
+
+	analyzer := &HomebrewDuplicates{hostArch: "arm64"}
+	result.Analyze(analyzer)
+
+	expected := map[string]string{
+		filepath.Join(armPrefix, "bin", "docker"):   "also installed in 1 other Homebrew prefix(es); keep this arm64 install",
+		filepath.Join(intelPrefix, "bin", "docker"): "duplicate of docker in " + armPrefix + " (arm64); remove with " + filepath.Join(intelPrefix, "bin", "brew") + " uninstall --cask docker",
+	}
+	for _, binary := range result.Binaries {
+		notes := binary.Notes
+		if isHomebrewCask(binary) {
+			notes = notes[1:]
+		}
+		want, ok := expected[binary.Path]
+		if !ok {
+			if len(notes) != 0 {
+				t.Errorf("Expected no duplicate notes for the docker formula, got %v", notes)
+			}
+			continue
+		}
+		if len(notes) != 1 || notes[0] != want {
+			t.Errorf("Expected note %q for %s, got %v", want, binary.Path, notes)
+		}
+	}
+}
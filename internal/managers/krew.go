@@ -0,0 +1,75 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// Krew implements the PackageManager interface for kubectl plugins
+// installed with krew
+type Krew struct {
+	executor system.CommandExecutor
+	root     string
+}
+
+// NewKrew creates a new krew package manager
+func NewKrew(executor system.CommandExecutor) *Krew {
+	root := os.Getenv("KREW_ROOT")
+	if root == "" {
+		root = filepath.Join(system.GetHomeDir(), ".krew")
+	}
+
+	return &Krew{
+		executor: executor,
+		root:     root,
+	}
+}
+
+// Name returns the name of the package manager
+func (k *Krew) Name() string {
+	return "krew"
+}
+
+// IsAvailable checks if krew has a receipts directory
+func (k *Krew) IsAvailable(ctx context.Context) bool {
+	return isDir(filepath.Join(k.root, "receipts"))
+}
+
+// Scan reads each plugin's receipt for its version, index and homepage.
+// The plugin's executable is the kubectl-<name> link krew creates in its
+// bin directory, with dashes in the name replaced by underscores.
+func (k *Krew) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	receipts, err := filepath.Glob(filepath.Join(k.root, "receipts", "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var binaries []*scanner.Binary
+	for _, receipt := range receipts {
+		doc, err := readYAML(receipt)
+		if err != nil {
+			continue
+		}
+
+		plugin := doc.Values["metadata.name"]
+		if plugin == "" {
+			plugin = strings.TrimSuffix(filepath.Base(receipt), ".yaml")
+		}
+
+		// Plugins from custom indexes are installed as <index>/<name>
+		pkg := plugin
+		if index := doc.Values["status.source.name"]; index != "" && index != "default" {
+			pkg = index + "/" + plugin
+		}
+
+		name := "kubectl-" + strings.ReplaceAll(plugin, "-", "_")
+		binaries = append(binaries, pluginBinary(k.Name(), name, filepath.Join(k.root, "bin", name), doc.Values["spec.version"], pkg, doc.Values["spec.homepage"]))
+	}
+
+	return binaries, nil
+}
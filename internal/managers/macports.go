@@ -0,0 +1,116 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// MacPorts implements the PackageManager interface for MacPorts, which
+// installs into /opt/local
+type MacPorts struct {
+	executor system.CommandExecutor
+	prefix   string
+}
+
+// NewMacPorts creates a new MacPorts package manager
+func NewMacPorts(executor system.CommandExecutor) *MacPorts {
+	return &MacPorts{
+		executor: executor,
+		prefix:   "/opt/local",
+	}
+}
+
+// Name returns the name of the package manager
+func (m *MacPorts) Name() string {
+	return "macports"
+}
+
+// IsAvailable checks if port is installed
+func (m *MacPorts) IsAvailable(ctx context.Context) bool {
+	return m.executor.IsAvailable(ctx, "port")
+}
+
+// Scan lists the active ports and reports the executables each installed
+// into the prefix's bin and sbin directories
+func (m *MacPorts) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	output, err := m.executor.Execute(ctx, "port", "-q", "installed", "active")
+	if err != nil {
+		return nil, err
+	}
+	versions := parsePortInstalled(output)
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	output, err = m.executor.Execute(ctx, "port", "contents", "active")
+	if err != nil {
+		return nil, err
+	}
+
+	validator := system.NewFileValidator()
+	var binaries []*scanner.Binary
+	for _, file := range parsePortContents(output) {
+		dir := filepath.Dir(file.Path)
+		if dir != filepath.Join(m.prefix, "bin") && dir != filepath.Join(m.prefix, "sbin") {
+			continue
+		}
+		if !validator.IsBinaryExecutable(file.Path) {
+			continue
+		}
+
+		binaries = append(binaries, &scanner.Binary{
+			Name:    filepath.Base(file.Path),
+			Path:    file.Path,
+			Manager: m.Name(),
+			Version: versions[file.Port],
+			Package: file.Port,
+			Prefix:  m.prefix,
+		})
+	}
+
+	return binaries, nil
+}
+
+// portFile is a file installed by a port
+type portFile struct {
+	Port string
+	Path string
+}
+
+// parsePortInstalled reads `port -q installed` output, lines such as
+// "  curl @8.5.0_0+ssl (active)", into each port's version and revision
+func parsePortInstalled(output string) map[string]string {
+	versions := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "@") {
+			continue
+		}
+		// Variants follow the version, e.g. +ssl+http2
+		version, _, _ := strings.Cut(strings.TrimPrefix(fields[1], "@"), "+")
+		versions[fields[0]] = version
+	}
+	return versions
+}
+
+// parsePortContents reads `port contents` output, where each port's files
+// follow a "Port <name> [@<version>] contains:" header
+func parsePortContents(output string) []portFile {
+	var files []portFile
+	port := ""
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if header, ok := strings.CutPrefix(trimmed, "Port "); ok && strings.HasSuffix(header, " contains:") {
+			port = strings.Fields(header)[0]
+			continue
+		}
+		if port != "" && strings.HasPrefix(trimmed, "/") {
+			files = append(files, portFile{Port: port, Path: trimmed})
+		}
+	}
+	return files
+}
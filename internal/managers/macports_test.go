@@ -0,0 +1,47 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+func TestMacPortsScanConflictsWithHomebrew(t *testing.T) {
+	prefix := t.TempDir()
+	writeFile(t, filepath.Join(prefix, "bin", "curl"), "", 0755)
+	writeFile(t, filepath.Join(prefix, "bin", "curl-config"), "", 0755)
+	writeFile(t, filepath.Join(prefix, "sbin", "nginx"), "", 0755)
+
+	executor := &fakeExecutor{outputs: map[string]string{
+		"port -q installed active": "  curl @8.5.0_0+ssl (active)\n  nginx @1.25.3_0 (active)\n",
+		"port contents active": "Port curl @8.5.0_0+ssl contains:\n  " + filepath.Join(prefix, "bin", "curl") + "\n  " + filepath.Join(prefix, "bin", "curl-config") + "\n  " + filepath.Join(prefix, "share", "man", "man1", "curl.1.gz") +
+			"\nPort nginx contains:\n  " + filepath.Join(prefix, "sbin", "nginx") + "\n",
+	}}
+
+	binaries, err := (&MacPorts{executor: executor, prefix: prefix}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binaries) != 3 {
+		t.Fatalf("Expected 3 binaries, got %d", len(binaries))
+	}
+	if curl := findBinary(binaries, "curl"); curl == nil || curl.Version != "8.5.0_0" || curl.Package != "curl" || curl.Prefix != prefix {
+		t.Errorf("Expected curl 8.5.0_0, got %+v", curl)
+	}
+	if nginx := findBinary(binaries, "nginx"); nginx == nil || nginx.Version != "1.25.3_0" {
+		t.Errorf("Expected nginx 1.25.3_0, got %+v", nginx)
+	}
+
+	result := scanner.NewScanResult()
+	for _, binary := range binaries {
+		result.AddBinary(binary)
+	}
+	result.AddBinary(&scanner.Binary{Name: "curl", Path: "/opt/homebrew/bin/curl", Manager: "homebrew", Version: "8.6.0", Package: "curl"})
+	result.DetectConflicts()
+
+	if len(result.Conflicts["curl"]) != 2 {
+		t.Errorf("Expected the port and formula of curl to conflict, got %v", result.Conflicts["curl"])
+	}
+}
@@ -2,8 +2,11 @@ package managers
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/alexcloudstar/snappoint/internal/scanner"
 	"github.com/alexcloudstar/snappoint/pkg/system"
@@ -36,6 +39,12 @@ func (m *Manual) IsAvailable(ctx context.Context) bool {
 // SetKnownBinaries sets the list of binaries that are already managed by other package managers
 func (m *Manual) SetKnownBinaries(binaries []*scanner.Binary) {
 	for _, binary := range binaries {
+		// Ghosts found by other managers don't vouch for anything, and
+		// inactive installs, such as versions a version manager hasn't
+		// selected, aren't what runs under their name
+		if binary.IsGhost() || binary.Inactive {
+			continue
+		}
 		key := filepath.Base(binary.Path)
 		m.knownBinaries[key] = true
 	}
@@ -49,7 +58,7 @@ func (m *Manual) Scan(ctx context.Context) ([]*scanner.Binary, error) {
 	dirsToScan := system.GetCommonBinaryPaths()
 
 	for _, dir := range dirsToScan {
-		bins, err := m.scanDirectory(dir)
+		bins, err := m.scanDirectory(ctx, dir)
 		if err != nil {
 			// Skip directories that can't be scanned
 			continue
@@ -61,7 +70,7 @@ func (m *Manual) Scan(ctx context.Context) ([]*scanner.Binary, error) {
 }
 
 // scanDirectory scans a single directory for binaries
-func (m *Manual) scanDirectory(dir string) ([]*scanner.Binary, error) {
+func (m *Manual) scanDirectory(ctx context.Context, dir string) ([]*scanner.Binary, error) {
 	var binaries []*scanner.Binary
 
 	entries, err := os.ReadDir(dir)
@@ -99,8 +108,86 @@ func (m *Manual) scanDirectory(dir string) ([]*scanner.Binary, error) {
 			Manager: m.Name(),
 			Version: "unknown",
 			Package: "",
+			Origin:  m.checkoutOrigin(ctx, fullPath),
 		})
 	}
 
 	return binaries, nil
 }
+
+// checkoutOrigin describes the git checkout a symlinked ghost points into:
+// its remote, branch, commit, uncommitted changes and how far it's behind
+// the branch it tracks, as of the last fetch. It returns nil for ghosts
+// that aren't links into a working tree.
+func (m *Manual) checkoutOrigin(ctx context.Context, path string) *scanner.Origin {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return nil
+	}
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil
+	}
+	workTree, gd := findGitWorkTree(target)
+	if workTree == "" {
+		return nil
+	}
+
+	branch, commit := readGitHead(gd)
+	remote := "origin"
+	if branch != "" {
+		if r := readGitConfig(gd, `branch "`+branch+`"`, "remote"); r != "" && r != "." {
+			remote = r
+		}
+	}
+
+	story := "a git checkout in " + workTree
+	if url := readGitRemoteURL(gd, remote); url != "" {
+		story = "cloned from " + url + " into " + workTree
+	}
+
+	state := []string{"detached at " + shortCommit(commit)}
+	if branch != "" {
+		state = []string{branch + " at " + shortCommit(commit)}
+	}
+	if m.executor != nil && m.executor.IsAvailable(ctx, "git") {
+		if status, err := m.executor.Execute(ctx, "git", "-C", workTree, "status", "--porcelain", "--untracked-files=no"); err == nil && strings.TrimSpace(status) != "" {
+			state = append(state, "uncommitted changes")
+		}
+		if behind := m.commitsBehind(ctx, workTree, gd, branch, commit); behind != "" {
+			state = append(state, behind)
+		}
+	}
+
+	return &scanner.Origin{
+		Story:      fmt.Sprintf("%s (%s)", story, strings.Join(state, ", ")),
+		Confidence: "high",
+	}
+}
+
+// commitsBehind describes how many commits a checkout's branch is behind
+// its upstream's remote-tracking ref, without fetching
+func (m *Manual) commitsBehind(ctx context.Context, workTree, gd, branch, commit string) string {
+	if branch == "" {
+		return ""
+	}
+	upstream := readGitUpstream(gd, branch)
+	if upstream == "" || resolveGitRef(gd, upstream) == commit {
+		return ""
+	}
+
+	output, err := m.executor.Execute(ctx, "git", "-C", workTree, "rev-list", "--count", "HEAD.."+upstream)
+	if err != nil {
+		return ""
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil || count == 0 {
+		return ""
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(upstream, "refs/remotes/"), "refs/heads/")
+	if count == 1 {
+		return "1 commit behind " + name
+	}
+	return fmt.Sprintf("%d commits behind %s", count, name)
+}
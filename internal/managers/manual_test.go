@@ -0,0 +1,67 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+func TestManualCheckoutOrigin(t *testing.T) {
+	root := t.TempDir()
+	checkout := filepath.Join(root, "src", "dotfiles")
+	gitDir := filepath.Join(checkout, ".git")
+
+	writeFile(t, filepath.Join(checkout, "scripts", "sync"), "#!/bin/sh\n", 0755)
+	writeFile(t, filepath.Join(gitDir, "HEAD"), "ref: refs/heads/main\n", 0644)
+	writeFile(t, filepath.Join(gitDir, "refs", "heads", "main"), "1111111111111111111111111111111111111111\n", 0644)
+	writeFile(t, filepath.Join(gitDir, "packed-refs"), "# pack-refs with: peeled fully-peeled sorted\n2222222222222222222222222222222222222222 refs/remotes/origin/main\n", 0644)
+	writeFile(t, filepath.Join(gitDir, "config"), "[core]\n\tbare = false\n[remote \"origin\"]\n\turl = git@github.com:me/dotfiles.git\n[branch \"main\"]\n\tremote = origin\n\tmerge = refs/heads/main\n", 0644)
+
+	symlink(t, filepath.Join(checkout, "scripts", "sync"), filepath.Join(root, "bin", "sync"))
+	writeFile(t, filepath.Join(root, "bin", "plain"), "", 0755)
+	writeFile(t, filepath.Join(root, "other", "tool"), "", 0755)
+	symlink(t, filepath.Join(root, "other", "tool"), filepath.Join(root, "bin", "tool"))
+
+	executor := &fakeExecutor{
+		available: map[string]bool{"git": true},
+		outputs: map[string]string{
+			"git -C " + checkout + " status --porcelain --untracked-files=no":         " M scripts/sync\n",
+			"git -C " + checkout + " rev-list --count HEAD..refs/remotes/origin/main": "3\n",
+		},
+	}
+	manual := NewManual(executor)
+
+	origin := manual.checkoutOrigin(context.Background(), filepath.Join(root, "bin", "sync"))
+	expected := "cloned from git@github.com:me/dotfiles.git into " + checkout + " (main at 1111111, uncommitted changes, 3 commits behind origin/main)"
+	if origin == nil || origin.Story != expected || origin.Confidence != "high" {
+		t.Errorf("Expected origin %q, got %+v", expected, origin)
+	}
+
+	for _, name := range []string{"plain", "tool"} {
+		if origin := manual.checkoutOrigin(context.Background(), filepath.Join(root, "bin", name)); origin != nil {
+			t.Errorf("Expected no origin for %s, got %+v", name, origin)
+		}
+	}
+}
+
+func TestManualSetKnownBinaries(t *testing.T) {
+	bin := t.TempDir()
+	writeFile(t, filepath.Join(bin, "node"), "", 0755)
+	writeFile(t, filepath.Join(bin, "rg"), "", 0755)
+
+	manual := NewManual(nil)
+	manual.SetKnownBinaries([]*scanner.Binary{
+		{Name: "node", Path: "/home/me/.asdf/installs/nodejs/18.19.0/bin/node", Manager: "asdf", Inactive: true},
+		{Name: "rg", Path: "/opt/homebrew/bin/rg", Manager: "homebrew"},
+	})
+
+	binaries, err := manual.scanDirectory(context.Background(), bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binaries) != 1 || binaries[0].Name != "node" {
+		t.Errorf("Expected only node to be a ghost, since inactive installs don't claim names, got %v", binaries)
+	}
+}
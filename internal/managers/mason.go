@@ -0,0 +1,129 @@
+package managers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// Mason implements the PackageManager interface for language servers,
+// formatters and linters installed by mason.nvim
+type Mason struct {
+	executor system.CommandExecutor
+	dir      string
+}
+
+// masonReceipt is the subset of a package's mason-receipt.json we use
+type masonReceipt struct {
+	Name          string `json:"name"`
+	PrimarySource struct {
+		Type    string `json:"type"`
+		ID      string `json:"id"`      // A purl, e.g. pkg:npm/pyright@1.1.350
+		Package string `json:"package"` // Older receipts name the package instead
+	} `json:"primary_source"`
+	Links struct {
+		Bin map[string]string `json:"bin"`
+	} `json:"links"`
+}
+
+// NewMason creates a new mason.nvim package manager
+func NewMason(executor system.CommandExecutor) *Mason {
+	appName := os.Getenv("NVIM_APPNAME")
+	if appName == "" {
+		appName = "nvim"
+	}
+
+	return &Mason{
+		executor: executor,
+		dir:      filepath.Join(xdgDir("", "XDG_DATA_HOME", filepath.Join(".local", "share"), appName), "mason"),
+	}
+}
+
+// Name returns the name of the package manager
+func (m *Mason) Name() string {
+	return "mason"
+}
+
+// IsAvailable checks if mason has a packages directory
+func (m *Mason) IsAvailable(ctx context.Context) bool {
+	return isDir(filepath.Join(m.dir, "packages"))
+}
+
+// Scan reads each package's receipt for the executables it links into
+// mason's bin directory and the version in its source purl
+func (m *Mason) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	receipts, err := filepath.Glob(filepath.Join(m.dir, "packages", "*", "mason-receipt.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	validator := system.NewFileValidator()
+	var binaries []*scanner.Binary
+
+	for _, path := range receipts {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var receipt masonReceipt
+		if err := json.Unmarshal(data, &receipt); err != nil {
+			continue
+		}
+
+		pkg := receipt.Name
+		if pkg == "" {
+			pkg = filepath.Base(filepath.Dir(path))
+		}
+
+		source := receipt.PrimarySource.ID
+		if source == "" && receipt.PrimarySource.Package != "" {
+			source = receipt.PrimarySource.Type + ":" + receipt.PrimarySource.Package
+		}
+
+		// Links are a map, so sort them for a stable order
+		names := make([]string, 0, len(receipt.Links.Bin))
+		for name := range receipt.Links.Bin {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			binPath := filepath.Join(m.dir, "bin", name)
+			if !validator.IsBinaryExecutable(binPath) {
+				continue
+			}
+
+			binaries = append(binaries, &scanner.Binary{
+				Name:    name,
+				Path:    binPath,
+				Manager: m.Name(),
+				Version: purlVersion(receipt.PrimarySource.ID),
+				Package: pkg,
+				Source:  source,
+			})
+		}
+	}
+
+	return binaries, nil
+}
+
+// purlVersion returns the version of a package URL such as
+// pkg:github/LuaLS/lua-language-server@3.7.4 or pkg:npm/%40angular/cli@17.0.0
+func purlVersion(purl string) string {
+	// Qualifiers and subpaths follow the version
+	purl, _, _ = strings.Cut(purl, "?")
+	purl, _, _ = strings.Cut(purl, "#")
+
+	idx := strings.LastIndex(purl, "@")
+	if idx < 0 || strings.HasSuffix(purl[:idx], "/") {
+		return ""
+	}
+	return purl[idx+1:]
+}
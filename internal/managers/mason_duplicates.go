@@ -0,0 +1,62 @@
+package managers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// masonDuplicateManagers lists the global installers whose tools mason
+// commonly duplicates
+var masonDuplicateManagers = map[string]bool{
+	"npm":      true,
+	"pip":      true,
+	"uv":       true,
+	"homebrew": true,
+}
+
+// MasonDuplicates reports tools installed both by mason.nvim and globally
+// through npm, pip, uv or Homebrew. mason prepends its bin directory to
+// PATH inside Neovim only, so the editor and the shell run different copies.
+type MasonDuplicates struct {
+	lookPath func(name string) string
+}
+
+// NewMasonDuplicates creates a mason duplicate detector
+func NewMasonDuplicates() *MasonDuplicates {
+	return &MasonDuplicates{
+		lookPath: system.LookPath,
+	}
+}
+
+// Analyze annotates each mason binary that has a global twin with the
+// twin's package and version and which copy the shell resolves
+func (d *MasonDuplicates) Analyze(result *scanner.ScanResult) {
+	byName := make(map[string][]*scanner.Binary)
+	for _, binary := range result.Binaries {
+		if !binary.Inactive && masonDuplicateManagers[binary.Manager] {
+			byName[binary.Name] = append(byName[binary.Name], binary)
+		}
+	}
+
+	for _, binary := range result.Binaries {
+		if binary.Manager != "mason" {
+			continue
+		}
+
+		for _, twin := range byName[binary.Name] {
+			twinName := strings.Join(strings.Fields(twin.Manager+" "+twin.Package+" "+twin.Version), " ")
+			note := fmt.Sprintf("duplicate of %s (%s)", twinName, twin.Path)
+			if twin.Version != "" && binary.Version != "" && twin.Version != binary.Version {
+				note += fmt.Sprintf(", versions differ (%s vs %s)", binary.Version, twin.Version)
+			}
+
+			if shell := d.lookPath(binary.Name); shell != "" && shell != binary.Path {
+				note += ", Neovim runs mason's copy and the shell runs " + shell
+			}
+			binary.Notes = append(binary.Notes, note)
+		}
+	}
+}
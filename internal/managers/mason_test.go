@@ -0,0 +1,66 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+func TestMasonScan(t *testing.T) {
+	dir := t.TempDir()
+
+	pyright := filepath.Join(dir, "packages", "pyright")
+	writeFile(t, filepath.Join(pyright, "mason-receipt.json"), `{
+  "name": "pyright",
+  "schema_version": "1.2",
+  "primary_source": {"type": "registry+v1", "id": "pkg:npm/pyright@1.1.350"},
+  "links": {"bin": {"pyright": "node_modules/.bin/pyright", "pyright-langserver": "node_modules/.bin/pyright-langserver"}, "share": {}, "opt": {}}
+}`, 0644)
+	writeFile(t, filepath.Join(pyright, "node_modules", ".bin", "pyright"), "", 0755)
+	writeFile(t, filepath.Join(pyright, "node_modules", ".bin", "pyright-langserver"), "", 0755)
+	symlink(t, filepath.Join(pyright, "node_modules", ".bin", "pyright"), filepath.Join(dir, "bin", "pyright"))
+	symlink(t, filepath.Join(pyright, "node_modules", ".bin", "pyright-langserver"), filepath.Join(dir, "bin", "pyright-langserver"))
+
+	binaries, err := (&Mason{dir: dir}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(binaries) != 2 {
+		t.Fatalf("Expected 2 binaries, got %d", len(binaries))
+	}
+	if binaries[0].Name != "pyright" || binaries[1].Name != "pyright-langserver" {
+		t.Errorf("Expected links in name order, got %s and %s", binaries[0].Name, binaries[1].Name)
+	}
+	for _, binary := range binaries {
+		if binary.Package != "pyright" || binary.Version != "1.1.350" || binary.Source != "pkg:npm/pyright@1.1.350" {
+			t.Errorf("Unexpected mason binary: %+v", binary)
+		}
+	}
+}
+
+func TestMasonDuplicatesAnalyze(t *testing.T) {
+	result := scanner.NewScanResult()
+	mason := &scanner.Binary{Name: "pyright", Path: "/home/me/.local/share/nvim/mason/bin/pyright", Manager: "mason", Version: "1.1.350", Package: "pyright"}
+	npm := &scanner.Binary{Name: "pyright", Path: "/usr/local/bin/pyright", Manager: "npm", Version: "1.1.340", Package: "pyright"}
+	other := &scanner.Binary{Name: "stylua", Path: "/home/me/.local/share/nvim/mason/bin/stylua", Manager: "mason", Version: "0.20.0", Package: "stylua"}
+	result.AddBinary(mason)
+	result.AddBinary(npm)
+	result.AddBinary(other)
+
+	analyzer := &MasonDuplicates{lookPath: func(name string) string { return "/usr/local/bin/" + name }}
+	analyzer.Analyze(result)
+
+	notes := strings.Join(mason.Notes, "; ")
+	for _, want := range []string{"duplicate of npm pyright 1.1.340 (/usr/local/bin/pyright)", "versions differ (1.1.350 vs 1.1.340)", "the shell runs /usr/local/bin/pyright"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("Expected note to contain %q, got %q", want, notes)
+		}
+	}
+	if len(other.Notes) != 0 {
+		t.Errorf("Expected no notes for stylua, got %v", other.Notes)
+	}
+}
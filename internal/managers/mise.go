@@ -0,0 +1,99 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// miseConfigFiles lists the per-directory config files mise reads, highest
+// precedence first
+var miseConfigFiles = []string{
+	"mise.local.toml",
+	"mise.toml",
+	".mise.local.toml",
+	".mise.toml",
+	filepath.Join(".config", "mise.toml"),
+	filepath.Join(".config", "mise", "config.toml"),
+}
+
+// Mise implements the PackageManager interface for the mise version manager
+type Mise struct {
+	executor  system.CommandExecutor
+	dataDir   string
+	configDir string
+	workDir   string
+}
+
+// NewMise creates a new mise package manager
+func NewMise(executor system.CommandExecutor) *Mise {
+	workDir, _ := os.Getwd()
+
+	return &Mise{
+		executor:  executor,
+		dataDir:   xdgDir("MISE_DATA_DIR", "XDG_DATA_HOME", filepath.Join(".local", "share"), "mise"),
+		configDir: xdgDir("MISE_CONFIG_DIR", "XDG_CONFIG_HOME", ".config", "mise"),
+		workDir:   workDir,
+	}
+}
+
+// Name returns the name of the package manager
+func (m *Mise) Name() string {
+	return "mise"
+}
+
+// IsAvailable checks if mise has an installs directory
+func (m *Mise) IsAvailable(ctx context.Context) bool {
+	info, err := os.Stat(filepath.Join(m.dataDir, "installs"))
+	return err == nil && info.IsDir()
+}
+
+// Scan discovers all tool versions installed by mise
+func (m *Mise) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	requests := findToolRequests(m.workDir, readMiseDir)
+	requests.merge(parseMiseTOML(filepath.Join(m.configDir, "config.toml")))
+	requests.merge(parseToolVersions(filepath.Join(system.GetHomeDir(), toolVersionsFile)))
+
+	active := requests.activeVersion("MISE")
+
+	// mise shims are symlinks to the mise binary itself, so the only way to
+	// map them back is by the executables each install provides
+	shimTool := func(shim string, providers []string) string {
+		if len(providers) > 0 {
+			return providers[0]
+		}
+		return ""
+	}
+
+	installsDir := filepath.Join(m.dataDir, "installs")
+	versionDir := func(tool, version string) string {
+		return filepath.Join(installsDir, tool, version)
+	}
+
+	return versionManagerInstalls(m.Name(), readInstalls(installsDir), versionDir, filepath.Join(m.dataDir, "shims"), shimTool, active), nil
+}
+
+// readMiseDir reads every mise config file and .tool-versions in a directory
+func readMiseDir(dir string) toolRequests {
+	requests := make(toolRequests)
+	for _, name := range miseConfigFiles {
+		requests.merge(parseMiseTOML(filepath.Join(dir, name)))
+	}
+	requests.merge(parseToolVersions(filepath.Join(dir, toolVersionsFile)))
+	return requests
+}
+
+// xdgDir resolves a tool directory from its own override variable, then the
+// XDG base directory variable, then the XDG default under $HOME
+func xdgDir(overrideEnv, xdgEnv, xdgDefault, name string) string {
+	if dir := os.Getenv(overrideEnv); dir != "" {
+		return dir
+	}
+	if base := os.Getenv(xdgEnv); base != "" {
+		return filepath.Join(base, name)
+	}
+	return filepath.Join(system.GetHomeDir(), xdgDefault, name)
+}
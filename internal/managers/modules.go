@@ -0,0 +1,393 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// tclVariablePattern matches $name, ${name} and $env(NAME) in Tcl modulefiles
+var tclVariablePattern = regexp.MustCompile(`\$(?:\{(\w+)\}|::env\((\w+)\)|env\((\w+)\)|(\w+))`)
+
+// luaPathCallPattern matches the path-modifying calls of Lua modulefiles,
+// e.g. prepend_path("PATH", pathJoin(base, "bin"))
+var luaPathCallPattern = regexp.MustCompile(`^\s*(prepend_path|append_path)\s*[({](.*)[)}]\s*$`)
+
+// luaLocalPattern matches `local name = <expression>` in Lua modulefiles
+var luaLocalPattern = regexp.MustCompile(`^\s*local\s+(\w+)\s*=\s*(.+?)\s*$`)
+
+// Modules implements the PackageManager interface for Lmod and Environment
+// Modules, attributing the executables in the bin directories each loaded
+// module adds to PATH to that module
+type Modules struct {
+	executor    system.CommandExecutor
+	modulePath  []string
+	loaded      []string // Loaded modules as name/version, in load order
+	loadedFiles []string // Their modulefiles, when the module system records them
+}
+
+// loadedModule is a loaded module and the changes it makes to PATH
+type loadedModule struct {
+	Name    string
+	Version string
+	File    string
+	Paths   []pathChange
+}
+
+// pathChange is one prepend-path or append-path of PATH in a modulefile
+type pathChange struct {
+	Dirs   []string
+	Append bool
+}
+
+// NewModules creates a new Lmod / Environment Modules package manager
+func NewModules(executor system.CommandExecutor) *Modules {
+	return &Modules{
+		executor:    executor,
+		modulePath:  splitPathList(os.Getenv("MODULEPATH")),
+		loaded:      splitPathList(os.Getenv("LOADEDMODULES")),
+		loadedFiles: splitPathList(os.Getenv("_LMFILES_")),
+	}
+}
+
+// Name returns the name of the package manager
+func (m *Modules) Name() string {
+	return "module"
+}
+
+// IsAvailable checks if a module system is set up in this shell
+func (m *Modules) IsAvailable(ctx context.Context) bool {
+	return len(m.modulePath) > 0 || len(m.loaded) > 0
+}
+
+// Scan reports the executables in every bin directory a loaded module
+// prepends or appends to PATH. When modules provide the same command, the
+// one whose directory comes first on PATH once every module is loaded wins
+// and the others are reported as inactive.
+func (m *Modules) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	validator := system.NewFileValidator()
+	modules := m.loadedModules()
+	rank := modulePathOrder(modules)
+
+	seenDirs := make(map[string]bool)
+	providers := make(map[*scanner.Binary]*loadedModule)
+	winners := make(map[string]*scanner.Binary)
+
+	var binaries []*scanner.Binary
+	for _, module := range modules {
+		for _, change := range module.Paths {
+			for _, dir := range change.Dirs {
+				if seenDirs[dir] {
+					continue
+				}
+				seenDirs[dir] = true
+
+				for _, name := range validator.ListExecutables(dir) {
+					binary := &scanner.Binary{
+						Name:    name,
+						Path:    filepath.Join(dir, name),
+						Manager: m.Name(),
+						Version: module.Version,
+						Package: module.Name,
+						Source:  module.File,
+					}
+					providers[binary] = module
+					binaries = append(binaries, binary)
+
+					if winner, ok := winners[name]; !ok || rank[dir] < rank[filepath.Dir(winner.Path)] {
+						winners[name] = binary
+					}
+				}
+			}
+		}
+	}
+
+	for _, binary := range binaries {
+		if winner := winners[binary.Name]; winner != binary {
+			module := providers[winner]
+			binary.Inactive = true
+			binary.Notes = append(binary.Notes, "shadowed by module "+module.Name+"/"+module.Version)
+		}
+	}
+
+	return binaries, nil
+}
+
+// modulePathOrder replays the PATH changes of the loaded modules in load
+// order, returning each directory's position on the resulting PATH. Each
+// prepend-path puts its directories in front of those already added and
+// each append-path puts them behind, moving any that were added before.
+func modulePathOrder(modules []*loadedModule) map[string]int {
+	var order []string
+	for _, module := range modules {
+		for _, change := range module.Paths {
+			moved := make(map[string]bool)
+			for _, dir := range change.Dirs {
+				moved[dir] = true
+			}
+
+			kept := make([]string, 0, len(order)+len(change.Dirs))
+			if !change.Append {
+				kept = append(kept, change.Dirs...)
+			}
+			for _, dir := range order {
+				if !moved[dir] {
+					kept = append(kept, dir)
+				}
+			}
+			if change.Append {
+				kept = append(kept, change.Dirs...)
+			}
+			order = kept
+		}
+	}
+
+	rank := make(map[string]int)
+	for i, dir := range order {
+		if _, ok := rank[dir]; !ok {
+			rank[dir] = i
+		}
+	}
+	return rank
+}
+
+// loadedModules finds the modulefile of each loaded module and the changes
+// it makes to PATH
+func (m *Modules) loadedModules() []*loadedModule {
+	var modules []*loadedModule
+	for i, spec := range m.loaded {
+		name, version := spec, ""
+		if idx := strings.LastIndex(spec, "/"); idx > 0 {
+			name, version = spec[:idx], spec[idx+1:]
+		}
+
+		file := ""
+		if len(m.loadedFiles) == len(m.loaded) {
+			file = m.loadedFiles[i]
+		} else {
+			file = m.findModulefile(spec)
+		}
+		if file == "" {
+			continue
+		}
+
+		modules = append(modules, &loadedModule{
+			Name:    name,
+			Version: version,
+			File:    file,
+			Paths:   parseModulefile(file, name, version),
+		})
+	}
+	return modules
+}
+
+// findModulefile looks for a module's Tcl or Lua modulefile in MODULEPATH
+func (m *Modules) findModulefile(spec string) string {
+	for _, dir := range m.modulePath {
+		for _, candidate := range []string{filepath.Join(dir, spec), filepath.Join(dir, spec+".lua")} {
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// parseModulefile returns the changes a modulefile makes to PATH, in Tcl
+// (prepend-path PATH …) or, for .lua files, Lmod's Lua syntax
+// (prepend_path("PATH", …)). Variables set earlier in the file are
+// expanded; paths built from anything else are skipped.
+func parseModulefile(path, name, version string) []pathChange {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	if strings.HasSuffix(path, ".lua") {
+		return parseLuaModulefile(string(data), name, version)
+	}
+	return parseTclModulefile(string(data))
+}
+
+// parseTclModulefile reads the PATH changes of a Tcl modulefile
+func parseTclModulefile(data string) []pathChange {
+	vars := make(map[string]string)
+
+	var changes []pathChange
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		// Command substitutions such as [module-info name] can't be evaluated
+		if len(fields) < 3 || strings.ContainsAny(line, "[]") {
+			continue
+		}
+
+		switch fields[0] {
+		case "set":
+			if value, ok := expandTcl(strings.Join(fields[2:], " "), vars); ok {
+				vars[fields[1]] = value
+			}
+		case "prepend-path", "append-path":
+			// Options such as -d ":" come before the variable name
+			args := fields[1:]
+			for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+				if args[0] == "-d" || args[0] == "--delim" {
+					args = args[1:]
+				}
+				args = args[1:]
+			}
+			if len(args) < 2 || args[0] != "PATH" {
+				continue
+			}
+			change := pathChange{Append: fields[0] == "append-path"}
+			for _, arg := range args[1:] {
+				if value, ok := expandTcl(arg, vars); ok {
+					change.Dirs = append(change.Dirs, splitPathList(value)...)
+				}
+			}
+			if len(change.Dirs) > 0 {
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes
+}
+
+// expandTcl substitutes variables and environment variables into a Tcl
+// word, reporting false when it uses a variable that isn't set
+func expandTcl(word string, vars map[string]string) (string, bool) {
+	word = strings.Trim(word, `"{}`)
+
+	ok := true
+	expanded := tclVariablePattern.ReplaceAllStringFunc(word, func(match string) string {
+		groups := tclVariablePattern.FindStringSubmatch(match)
+		if env := groups[2] + groups[3]; env != "" {
+			return os.Getenv(env)
+		}
+		value, found := vars[groups[1]+groups[4]]
+		if !found {
+			ok = false
+		}
+		return value
+	})
+	return expanded, ok
+}
+
+// parseLuaModulefile reads the PATH changes of a Lua modulefile
+func parseLuaModulefile(data, name, version string) []pathChange {
+	vars := make(map[string]string)
+
+	var changes []pathChange
+	for _, line := range strings.Split(data, "\n") {
+		if match := luaLocalPattern.FindStringSubmatch(line); match != nil {
+			if value, ok := evalLua(match[2], vars, name, version); ok {
+				vars[match[1]] = value
+			}
+			continue
+		}
+
+		match := luaPathCallPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		args := splitLuaArgs(match[2])
+		if len(args) < 2 || strings.Trim(args[0], `"'`) != "PATH" {
+			continue
+		}
+		if value, ok := evalLua(args[1], vars, name, version); ok {
+			changes = append(changes, pathChange{Dirs: splitPathList(value), Append: match[1] == "append_path"})
+		}
+	}
+	return changes
+}
+
+// evalLua evaluates the string expressions modulefiles build paths from:
+// literals, locals, os.getenv, pathJoin, myModuleName, myModuleVersion and
+// concatenation with ..
+func evalLua(expr string, vars map[string]string, name, version string) (string, bool) {
+	expr = strings.TrimSpace(expr)
+
+	if parts := splitLuaTopLevel(expr, ".."); len(parts) > 1 {
+		var sb strings.Builder
+		for _, part := range parts {
+			value, ok := evalLua(part, vars, name, version)
+			if !ok {
+				return "", false
+			}
+			sb.WriteString(value)
+		}
+		return sb.String(), true
+	}
+
+	switch {
+	case len(expr) >= 2 && (expr[0] == '"' || expr[0] == '\'') && expr[len(expr)-1] == expr[0]:
+		return expr[1 : len(expr)-1], true
+	case expr == "myModuleName()":
+		return name, true
+	case expr == "myModuleVersion()":
+		return version, true
+	case strings.HasPrefix(expr, "os.getenv(") && strings.HasSuffix(expr, ")"):
+		return os.Getenv(strings.Trim(expr[len("os.getenv("):len(expr)-1], `"' `)), true
+	case strings.HasPrefix(expr, "pathJoin(") && strings.HasSuffix(expr, ")"):
+		var parts []string
+		for _, arg := range splitLuaArgs(expr[len("pathJoin(") : len(expr)-1]) {
+			value, ok := evalLua(arg, vars, name, version)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, value)
+		}
+		return filepath.Join(parts...), true
+	}
+
+	value, ok := vars[expr]
+	return value, ok
+}
+
+// splitLuaArgs splits a Lua argument list at its top-level commas
+func splitLuaArgs(args string) []string {
+	return splitLuaTopLevel(args, ",")
+}
+
+// splitLuaTopLevel splits a Lua expression at each sep outside of strings
+// and parentheses, trimming the parts
+func splitLuaTopLevel(expr, sep string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '{':
+			depth++
+		case c == ')' || c == '}':
+			depth--
+		case depth == 0 && strings.HasPrefix(expr[i:], sep):
+			parts = append(parts, strings.TrimSpace(expr[start:i]))
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, strings.TrimSpace(expr[start:]))
+}
+
+// splitPathList splits a colon-separated list such as MODULEPATH,
+// dropping empty entries
+func splitPathList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, string(os.PathListSeparator)) {
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
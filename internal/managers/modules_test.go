@@ -0,0 +1,95 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseModulefiles(t *testing.T) {
+	t.Setenv("APPS", "/apps")
+
+	tcl := parseTclModulefile(`#%Module1.0
+set root /opt/apps/gcc/12.2.0
+prepend-path PATH $root/bin
+prepend-path -d : PATH ${root}/libexec/bin:$env(APPS)/gcc/bin
+prepend-path LD_LIBRARY_PATH $root/lib64
+append-path PATH [module-info name]/bin
+append-path PATH $undefined/bin
+`)
+	expectedTcl := []pathChange{
+		{Dirs: []string{"/opt/apps/gcc/12.2.0/bin"}},
+		{Dirs: []string{"/opt/apps/gcc/12.2.0/libexec/bin", "/apps/gcc/bin"}},
+	}
+	if !reflect.DeepEqual(tcl, expectedTcl) {
+		t.Errorf("Expected Tcl dirs %v, got %v", expectedTcl, tcl)
+	}
+
+	lua := parseLuaModulefile(`help([[Python]])
+local base = pathJoin("/opt/apps", myModuleName(), myModuleVersion())
+local share = os.getenv("APPS") .. "/share"
+prepend_path("PATH", pathJoin(base, "bin"))
+prepend_path{"PATH", share .. "/bin", priority=100}
+append_path("PATH", "/opt/extra/bin:/opt/more/bin")
+prepend_path("MANPATH", pathJoin(base, "share/man"))
+prepend_path("PATH", unknown)
+`, "python", "3.11.4")
+	expectedLua := []pathChange{
+		{Dirs: []string{"/opt/apps/python/3.11.4/bin"}},
+		{Dirs: []string{"/apps/share/bin"}},
+		{Dirs: []string{"/opt/extra/bin", "/opt/more/bin"}, Append: true},
+	}
+	if !reflect.DeepEqual(lua, expectedLua) {
+		t.Errorf("Expected Lua dirs %v, got %v", expectedLua, lua)
+	}
+}
+
+func TestModulesScan(t *testing.T) {
+	root := t.TempDir()
+	modulePath := filepath.Join(root, "modulefiles")
+	gccRoot := filepath.Join(root, "apps", "gcc", "12.2.0")
+	llvmRoot := filepath.Join(root, "apps", "llvm", "17.0.6")
+	binutilsRoot := filepath.Join(root, "apps", "binutils", "2.41")
+
+	writeFile(t, filepath.Join(gccRoot, "bin", "gcc"), "", 0755)
+	writeFile(t, filepath.Join(gccRoot, "bin", "cpp"), "", 0755)
+	writeFile(t, filepath.Join(llvmRoot, "bin", "clang"), "", 0755)
+	writeFile(t, filepath.Join(llvmRoot, "bin", "cpp"), "", 0755)
+	writeFile(t, filepath.Join(modulePath, "gcc", "12.2.0"), "#%Module\nset root "+gccRoot+"\nprepend-path PATH $root/bin\n", 0644)
+	writeFile(t, filepath.Join(modulePath, "llvm", "17.0.6.lua"), "prepend_path(\"PATH\", \""+llvmRoot+"/bin\")\n", 0644)
+	// Loaded last, but appended behind the directories already on PATH
+	writeFile(t, filepath.Join(binutilsRoot, "bin", "cpp"), "", 0755)
+	writeFile(t, filepath.Join(binutilsRoot, "bin", "ld"), "", 0755)
+	writeFile(t, filepath.Join(modulePath, "binutils", "2.41"), "#%Module\nappend-path PATH "+binutilsRoot+"/bin\n", 0644)
+
+	modules := &Modules{modulePath: []string{modulePath}, loaded: []string{"gcc/12.2.0", "llvm/17.0.6", "binutils/2.41"}}
+	binaries, err := modules.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		filepath.Join(gccRoot, "bin", "gcc"):      "gcc 12.2.0 active",
+		filepath.Join(gccRoot, "bin", "cpp"):      "gcc 12.2.0 inactive",
+		filepath.Join(llvmRoot, "bin", "clang"):   "llvm 17.0.6 active",
+		filepath.Join(llvmRoot, "bin", "cpp"):     "llvm 17.0.6 active",
+		filepath.Join(binutilsRoot, "bin", "cpp"): "binutils 2.41 inactive",
+		filepath.Join(binutilsRoot, "bin", "ld"):  "binutils 2.41 active",
+	}
+	if len(binaries) != len(expected) {
+		t.Errorf("Expected %d binaries, got %d", len(expected), len(binaries))
+	}
+	for _, binary := range binaries {
+		state := "active"
+		if binary.Inactive {
+			state = "inactive"
+		}
+		if got := binary.Package + " " + binary.Version + " " + state; got != expected[binary.Path] {
+			t.Errorf("Expected %s to be %q, got %q", binary.Path, expected[binary.Path], got)
+		}
+		if binary.Inactive && (len(binary.Notes) == 0 || binary.Notes[0] != "shadowed by module llvm/17.0.6") {
+			t.Errorf("Expected %s to be shadowed by llvm, got %v", binary.Path, binary.Notes)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package managers
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// Perl implements the PackageManager interface for distributions installed
+// into a local::lib, usually by cpanm, which records each distribution's
+// files in a .packlist
+type Perl struct {
+	executor system.CommandExecutor
+	root     string
+}
+
+// perlDist is a distribution installed into a local::lib
+type perlDist struct {
+	Module  string // Main module, e.g. App::cpanminus
+	Version string
+}
+
+// NewPerl creates a new local::lib package manager
+func NewPerl(executor system.CommandExecutor) *Perl {
+	// The innermost active local::lib comes first
+	root, _, _ := strings.Cut(os.Getenv("PERL_LOCAL_LIB_ROOT"), string(os.PathListSeparator))
+	if root == "" {
+		root = filepath.Join(system.GetHomeDir(), "perl5")
+	}
+
+	return &Perl{
+		executor: executor,
+		root:     root,
+	}
+}
+
+// Name returns the name of the package manager
+func (p *Perl) Name() string {
+	return "cpan"
+}
+
+// IsAvailable checks if the local::lib bin directory exists
+func (p *Perl) IsAvailable(ctx context.Context) bool {
+	return isDir(filepath.Join(p.root, "bin"))
+}
+
+// Scan attributes each executable in the local::lib bin directory to the
+// distribution whose .packlist lists it. Executables no .packlist lists
+// are reported as ghosts.
+func (p *Perl) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	binDir := filepath.Join(p.root, "bin")
+	owners := p.readPacklists()
+
+	var binaries []*scanner.Binary
+	for _, name := range system.NewFileValidator().ListExecutables(binDir) {
+		path := filepath.Join(binDir, name)
+
+		dist, ok := owners[path]
+		if !ok {
+			binaries = append(binaries, ghostBinary(name, path))
+			continue
+		}
+
+		binaries = append(binaries, &scanner.Binary{
+			Name:    name,
+			Path:    path,
+			Manager: p.Name(),
+			Version: dist.Version,
+			Package: strings.ReplaceAll(dist.Module, "::", "-"),
+		})
+	}
+
+	return binaries, nil
+}
+
+// readPacklists maps every file listed in a .packlist under
+// lib/perl5/<archname>/auto/<Module/Path> to its distribution. Versions
+// come from the install.json cpanm writes into .meta.
+func (p *Perl) readPacklists() map[string]*perlDist {
+	libDir := filepath.Join(p.root, "lib", "perl5")
+	versions := readCpanmMeta(libDir)
+
+	owners := make(map[string]*perlDist)
+	autoDirs, _ := filepath.Glob(filepath.Join(libDir, "*", "auto"))
+	for _, auto := range autoDirs {
+		_ = filepath.WalkDir(auto, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.Name() != ".packlist" {
+				return nil
+			}
+			rel, err := filepath.Rel(auto, filepath.Dir(path))
+			if err != nil {
+				return nil
+			}
+
+			module := strings.ReplaceAll(filepath.ToSlash(rel), "/", "::")
+			dist := &perlDist{Module: module, Version: versions[module]}
+			for _, line := range readLines(path) {
+				// Entries may carry attributes, e.g. "<path> type=file"
+				if idx := strings.Index(line, " type="); idx >= 0 {
+					line = line[:idx]
+				}
+				owners[line] = dist
+			}
+			return nil
+		})
+	}
+	return owners
+}
+
+// readCpanmMeta maps each distribution's main module to its version, from
+// the lib/perl5/<archname>/.meta/<dist>/install.json files cpanm writes
+func readCpanmMeta(libDir string) map[string]string {
+	versions := make(map[string]string)
+	files, _ := filepath.Glob(filepath.Join(libDir, "*", ".meta", "*", "install.json"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		var meta struct {
+			Name    string          `json:"name"`
+			Version json.RawMessage `json:"version"`
+		}
+		if json.Unmarshal(data, &meta) != nil || meta.Name == "" {
+			continue
+		}
+		// Versions are written as strings or bare numbers
+		versions[meta.Name] = strings.Trim(string(meta.Version), `"`)
+	}
+	return versions
+}
@@ -0,0 +1,39 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestPerlScan(t *testing.T) {
+	root := t.TempDir()
+	arch := filepath.Join(root, "lib", "perl5", "x86_64-linux-gnu-thread-multi")
+
+	writeFile(t, filepath.Join(root, "bin", "cpanm"), "#!/usr/bin/perl\n", 0755)
+	writeFile(t, filepath.Join(root, "bin", "perltidy"), "#!/usr/bin/perl\n", 0755)
+	writeFile(t, filepath.Join(root, "bin", "stray"), "#!/usr/bin/perl\n", 0755)
+	writeFile(t, filepath.Join(arch, "auto", "App", "cpanminus", ".packlist"), filepath.Join(root, "bin", "cpanm")+"\n"+filepath.Join(root, "lib", "perl5", "App", "cpanminus.pm")+"\n", 0644)
+	writeFile(t, filepath.Join(arch, "auto", "Perl", "Tidy", ".packlist"), filepath.Join(root, "bin", "perltidy")+" type=file\n", 0644)
+	writeFile(t, filepath.Join(arch, ".meta", "App-cpanminus-1.7047", "install.json"), `{"name": "App::cpanminus", "version": "1.7047", "dist": "App-cpanminus-1.7047"}`, 0644)
+	writeFile(t, filepath.Join(arch, ".meta", "Perl-Tidy-20230912", "install.json"), `{"name": "Perl::Tidy", "version": 20230912}`, 0644)
+
+	binaries, err := (&Perl{root: root}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"cpanm":    "cpan App-cpanminus 1.7047",
+		"perltidy": "cpan Perl-Tidy 20230912",
+		"stray":    "manual  unknown",
+	}
+	if len(binaries) != len(expected) {
+		t.Errorf("Expected %d binaries, got %d", len(expected), len(binaries))
+	}
+	for _, binary := range binaries {
+		if got := binary.Manager + " " + binary.Package + " " + binary.Version; got != expected[binary.Name] {
+			t.Errorf("Expected %s to be %q, got %q", binary.Name, expected[binary.Name], got)
+		}
+	}
+}
@@ -117,6 +117,11 @@ func (p *Pip) getPackageBinaries(ctx context.Context, pipCmd, packageName, versi
 	for _, binDir := range binDirs {
 		binaryPath := filepath.Join(binDir, binaryName)
 
+		// Entrypoints linked in from uv or pipx tool environments belong to those tools
+		if isVirtualenvEntrypoint(binaryPath) {
+			continue
+		}
+
 		// Only add if binary exists and is executable
 		if validator.IsBinaryExecutable(binaryPath) {
 			binaries = append(binaries, &scanner.Binary{
@@ -0,0 +1,49 @@
+package managers
+
+import (
+	"os"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// pluginBinary records a CLI plugin. Plugins whose executable can't run are
+// kept, marked inactive so they don't take part in conflict detection, with
+// a note saying what's wrong.
+func pluginBinary(manager, name, path, version, pkg, source string) *scanner.Binary {
+	binary := &scanner.Binary{
+		Name:    name,
+		Path:    path,
+		Manager: manager,
+		Version: version,
+		Package: pkg,
+		Source:  source,
+	}
+
+	if problem := pluginProblem(path); problem != "" {
+		binary.Inactive = true
+		binary.Notes = append(binary.Notes, "broken plugin: "+problem)
+	}
+	return binary
+}
+
+// pluginProblem describes why a plugin executable can't run, or returns ""
+// if it can
+func pluginProblem(path string) string {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "executable is missing"
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		if _, err := os.Stat(path); err != nil {
+			target, _ := os.Readlink(path)
+			return "symlink target " + target + " is missing"
+		}
+	}
+
+	if !system.NewFileValidator().IsBinaryExecutable(path) {
+		return "file is not executable"
+	}
+	return ""
+}
@@ -0,0 +1,173 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+// findBinary returns the scanned binary with the given name, or nil
+func findBinary(binaries []*scanner.Binary, name string) *scanner.Binary {
+	for _, binary := range binaries {
+		if binary.Name == name {
+			return binary
+		}
+	}
+	return nil
+}
+
+func TestKrewScan(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "receipts", "view-secret.yaml"), `apiVersion: krew.googlecontainertools.github.com/v1alpha2
+kind: Plugin
+metadata:
+  name: view-secret
+spec:
+  version: v0.12.0
+  homepage: https://github.com/elsesiy/kubectl-view-secret
+  platforms:
+  - bin: kubectl-view-secret
+    uri: https://github.com/elsesiy/kubectl-view-secret/releases/download/v0.12.0/view-secret.tar.gz
+    selector:
+      matchLabels:
+        os: linux
+status:
+  source:
+    name: default
+`, 0644)
+	writeFile(t, filepath.Join(root, "receipts", "ctx.yaml"), "metadata:\n  name: ctx\nspec:\n  version: v0.9.5\n", 0644)
+
+	writeFile(t, filepath.Join(root, "store", "view-secret", "v0.12.0", "kubectl-view-secret"), "", 0755)
+	symlink(t, filepath.Join(root, "store", "view-secret", "v0.12.0", "kubectl-view-secret"), filepath.Join(root, "bin", "kubectl-view_secret"))
+	symlink(t, filepath.Join(root, "store", "ctx", "v0.9.5", "kubectl-ctx"), filepath.Join(root, "bin", "kubectl-ctx"))
+
+	binaries, err := (&Krew{root: root}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	viewSecret := findBinary(binaries, "kubectl-view_secret")
+	if viewSecret == nil || viewSecret.Version != "v0.12.0" || viewSecret.Source != "https://github.com/elsesiy/kubectl-view-secret" || viewSecret.Inactive {
+		t.Errorf("Unexpected view-secret plugin: %+v", viewSecret)
+	}
+
+	ctx := findBinary(binaries, "kubectl-ctx")
+	if ctx == nil || !ctx.Inactive || !strings.Contains(strings.Join(ctx.Notes, "; "), "broken plugin: symlink target") {
+		t.Errorf("Expected broken ctx plugin, got %+v", ctx)
+	}
+}
+
+func TestGhExtensionsScan(t *testing.T) {
+	dir := t.TempDir()
+
+	binaryExt := filepath.Join(dir, "gh-dash")
+	writeFile(t, filepath.Join(binaryExt, "manifest.yml"), "owner: dlvhdr\nname: gh-dash\nhost: github.com\ntag: v4.1.0\nispinned: false\npath: "+binaryExt+"/gh-dash\n", 0644)
+	writeFile(t, filepath.Join(binaryExt, "gh-dash"), "", 0755)
+
+	scriptExt := filepath.Join(dir, "gh-branch")
+	writeFile(t, filepath.Join(scriptExt, "gh-branch"), "#!/bin/sh\n", 0644)
+	writeFile(t, filepath.Join(scriptExt, ".git", "HEAD"), "ref: refs/heads/main\n", 0644)
+	writeFile(t, filepath.Join(scriptExt, ".git", "packed-refs"), "# pack-refs with: peeled fully-peeled sorted\n0123456789abcdef0123456789abcdef01234567 refs/heads/main\n", 0644)
+	writeFile(t, filepath.Join(scriptExt, ".git", "config"), "[core]\n\tbare = false\n[remote \"origin\"]\n\turl = git@github.com:mislav/gh-branch.git\n", 0644)
+
+	binaries, err := (&GhExtensions{dir: dir}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dash := findBinary(binaries, "gh-dash")
+	if dash == nil || dash.Version != "v4.1.0" || dash.Package != "dlvhdr/gh-dash" || dash.Source != "https://github.com/dlvhdr/gh-dash" {
+		t.Errorf("Unexpected gh-dash extension: %+v", dash)
+	}
+
+	branch := findBinary(binaries, "gh-branch")
+	if branch == nil || branch.Version != "0123456" || branch.Package != "mislav/gh-branch" {
+		t.Errorf("Unexpected gh-branch extension: %+v", branch)
+	}
+	if branch != nil && (!branch.Inactive || !strings.Contains(strings.Join(branch.Notes, "; "), "not executable")) {
+		t.Errorf("Expected non-executable gh-branch to be broken, got %v", branch.Notes)
+	}
+}
+
+func TestDockerPluginsScan(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "docker-compose")
+	writeFile(t, compose, "", 0755)
+	symlink(t, "/Applications/Docker.app/Contents/Resources/cli-plugins/docker-scout", filepath.Join(dir, "docker-scout"))
+	// Neither is loaded by the docker CLI, so neither is run
+	writeFile(t, filepath.Join(dir, "docker-compose.bak"), "", 0755)
+	writeFile(t, filepath.Join(dir, "docker-init"), "", 0644)
+
+	executor := &fakeExecutor{outputs: map[string]string{
+		compose + " docker-cli-plugin-metadata": `{"SchemaVersion":"0.1.0","Vendor":"Docker Inc.","Version":"v2.24.6","ShortDescription":"Docker Compose","URL":"https://github.com/docker/compose"}`,
+	}}
+
+	binaries, err := (&DockerPlugins{executor: executor, dirs: []string{dir}}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plugin := findBinary(binaries, "docker-compose"); plugin == nil || plugin.Version != "2.24.6" || plugin.Source != "https://github.com/docker/compose" {
+		t.Errorf("Unexpected compose plugin: %+v", plugin)
+	}
+	if plugin := findBinary(binaries, "docker-scout"); plugin == nil || !plugin.Inactive {
+		t.Errorf("Expected dangling scout plugin to be broken, got %+v", plugin)
+	}
+	if plugin := findBinary(binaries, "docker-init"); plugin == nil || !plugin.Inactive {
+		t.Errorf("Expected non-executable init plugin to be broken, got %+v", plugin)
+	}
+	if plugin := findBinary(binaries, "docker-compose.bak"); plugin != nil {
+		t.Errorf("Expected docker-compose.bak to be ignored, got %+v", plugin)
+	}
+	if len(executor.calls) != 1 {
+		t.Errorf("Expected metadata to be read from working plugins only, got %v", executor.calls)
+	}
+}
+
+func TestHelmPluginsScan(t *testing.T) {
+	dir := t.TempDir()
+
+	diff := filepath.Join(dir, "helm-diff")
+	writeFile(t, filepath.Join(diff, "plugin.yaml"), `name: "diff"
+version: "3.9.4"
+usage: "Preview helm upgrade changes as a diff"
+description: |-
+  Preview helm upgrade changes as a diff.
+command: "$HELM_PLUGIN_DIR/bin/diff"
+hooks:
+  install: "$HELM_PLUGIN_DIR/install-binary.sh"
+`, 0644)
+	writeFile(t, filepath.Join(diff, "bin", "diff"), "", 0755)
+
+	unittest := filepath.Join(dir, "helm-unittest")
+	writeFile(t, filepath.Join(unittest, "plugin.yaml"), "name: unittest\nversion: 0.5.1\ncommand: $HELM_PLUGIN_DIR/untt\n", 0644)
+
+	// Run through an interpreter, or only running programs from elsewhere
+	secrets := filepath.Join(dir, "helm-secrets")
+	writeFile(t, filepath.Join(secrets, "plugin.yaml"), "name: secrets\nversion: 4.6.0\ncommand: sh \"$HELM_PLUGIN_DIR/scripts/run.sh\"\n", 0644)
+	writeFile(t, filepath.Join(secrets, "scripts", "run.sh"), "#!/bin/sh\n", 0755)
+	pods := filepath.Join(dir, "helm-pods")
+	writeFile(t, filepath.Join(pods, "plugin.yaml"), "name: pods\nversion: 0.1.0\ncommand: sh -c 'kubectl get pods'\n", 0644)
+
+	binaries, err := (&HelmPlugins{dir: dir}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plugin := findBinary(binaries, "helm-diff"); plugin == nil || plugin.Version != "3.9.4" || plugin.Path != filepath.Join(diff, "bin", "diff") || plugin.Inactive {
+		t.Errorf("Unexpected diff plugin: %+v", plugin)
+	}
+	if plugin := findBinary(binaries, "helm-unittest"); plugin == nil || !strings.Contains(strings.Join(plugin.Notes, "; "), "executable is missing") {
+		t.Errorf("Expected unittest plugin with missing executable, got %+v", plugin)
+	}
+	if plugin := findBinary(binaries, "helm-secrets"); plugin == nil || plugin.Path != filepath.Join(secrets, "scripts", "run.sh") || plugin.Inactive {
+		t.Errorf("Expected secrets plugin to be recorded by its script, got %+v", plugin)
+	}
+	if plugin := findBinary(binaries, "helm-pods"); plugin == nil || plugin.Path != pods {
+		t.Errorf("Expected pods plugin to be recorded by its directory, got %+v", plugin)
+	}
+}
@@ -0,0 +1,125 @@
+package managers
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// gentooPackagePattern splits a Portage package directory such as
+// git-2.43.0-r1 into its name and version, including the revision
+var gentooPackagePattern = regexp.MustCompile(`^(.+?)-(\d[^-]*(?:-r\d+)?)$`)
+
+// Portage implements the PackageManager interface for Gentoo, claiming the
+// executables each installed package records in its CONTENTS file
+type Portage struct {
+	executor system.CommandExecutor
+	root     string // Filesystem root, "/" outside of tests and chroots
+}
+
+// NewPortage creates a new Portage package manager
+func NewPortage(executor system.CommandExecutor) *Portage {
+	return &Portage{
+		executor: executor,
+		root:     "/",
+	}
+}
+
+// Name returns the name of the package manager
+func (p *Portage) Name() string {
+	return "portage"
+}
+
+// IsAvailable checks if the installed package database exists
+func (p *Portage) IsAvailable(ctx context.Context) bool {
+	return isDir(p.dbDir())
+}
+
+// dbDir returns the installed package database, one <category>/<package>
+// directory per installed package
+func (p *Portage) dbDir() string {
+	return filepath.Join(p.root, "var", "db", "pkg")
+}
+
+// Scan reads every installed package's CONTENTS and reports the files and
+// links it installed into a bin or sbin directory
+func (p *Portage) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	contents, err := filepath.Glob(filepath.Join(p.dbDir(), "*", "*", "CONTENTS"))
+	if err != nil {
+		return nil, err
+	}
+
+	var binaries []*scanner.Binary
+	for _, file := range contents {
+		pkgDir := filepath.Dir(file)
+		category := filepath.Base(filepath.Dir(pkgDir))
+		name, version := filepath.Base(pkgDir), ""
+		if match := gentooPackagePattern.FindStringSubmatch(name); match != nil {
+			name, version = match[1], match[2]
+		}
+
+		for _, path := range readPortageContents(file) {
+			if base := filepath.Base(filepath.Dir(path)); base != "bin" && base != "sbin" {
+				continue
+			}
+			fullPath := filepath.Join(p.root, path)
+			if _, err := os.Lstat(fullPath); err != nil {
+				continue
+			}
+
+			binaries = append(binaries, &scanner.Binary{
+				Name:    filepath.Base(path),
+				Path:    fullPath,
+				Manager: p.Name(),
+				Version: version,
+				Package: category + "/" + name,
+			})
+		}
+	}
+
+	return binaries, nil
+}
+
+// readPortageContents returns the files and links listed in a CONTENTS
+// file, whose lines are "obj <path> <md5> <mtime>", "sym <path> -> <target>
+// <mtime>" or "dir <path>". Paths may contain spaces.
+func readPortageContents(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var paths []string
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		kind, rest, ok := strings.Cut(lineScanner.Text(), " ")
+		if !ok {
+			continue
+		}
+
+		switch kind {
+		case "obj":
+			// Drop the trailing checksum and mtime
+			end := len(rest)
+			for i := 0; i < 2 && end > 0; i++ {
+				end = strings.LastIndex(rest[:end], " ")
+			}
+			if end <= 0 {
+				continue
+			}
+			paths = append(paths, rest[:end])
+		case "sym":
+			if link, _, ok := strings.Cut(rest, " -> "); ok {
+				paths = append(paths, link)
+			}
+		}
+	}
+	return paths
+}
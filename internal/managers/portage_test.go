@@ -0,0 +1,37 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestPortageScan(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "var", "db", "pkg", "dev-vcs", "git-2.43.0-r1", "CONTENTS"),
+		"dir /usr\ndir /usr/bin\nobj /usr/bin/git 5d41402abc4b2a76b9719d911017c592 1700000000\nsym /usr/bin/git-upload-pack -> git 1700000000\nobj /usr/share/git-core/templates/description 5d41402abc4b2a76b9719d911017c592 1700000000\nobj /usr/bin/git-removed 5d41402abc4b2a76b9719d911017c592 1700000000\n", 0644)
+	writeFile(t, filepath.Join(root, "var", "db", "pkg", "app-misc", "my tool-1.0", "CONTENTS"),
+		"obj /opt/my tool/bin/run it 5d41402abc4b2a76b9719d911017c592 1700000000\n", 0644)
+	writeFile(t, filepath.Join(root, "usr", "bin", "git"), "", 0755)
+	symlink(t, "git", filepath.Join(root, "usr", "bin", "git-upload-pack"))
+	writeFile(t, filepath.Join(root, "opt", "my tool", "bin", "run it"), "", 0755)
+
+	binaries, err := (&Portage{root: root}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"git":             "dev-vcs/git 2.43.0-r1",
+		"git-upload-pack": "dev-vcs/git 2.43.0-r1",
+		"run it":          "app-misc/my tool 1.0",
+	}
+	if len(binaries) != len(expected) {
+		t.Errorf("Expected %d binaries, got %d", len(expected), len(binaries))
+	}
+	for _, binary := range binaries {
+		if got := binary.Package + " " + binary.Version; got != expected[binary.Name] {
+			t.Errorf("Expected %s from %q, got %q", binary.Name, expected[binary.Name], got)
+		}
+	}
+}
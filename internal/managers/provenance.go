@@ -0,0 +1,254 @@
+package managers
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// builtinProvenanceRules recognizes common curl | sh installers
+//
+//go:embed provenance_rules.json
+var builtinProvenanceRules []byte
+
+// maxSignatureScan bounds how much of a file is searched for signatures
+const maxSignatureScan = 64 << 20
+
+// ProvenanceRule recognizes binaries left by a known installer. Every
+// condition that is set must hold. Patterns may start with ~/ and use
+// {name} for the binary's name and {dir} for its directory; * matches
+// within a path segment and ** across segments.
+type ProvenanceRule struct {
+	Name       string   `json:"name"`
+	Story      string   `json:"story"`
+	Confidence string   `json:"confidence"`
+	Names      []string `json:"names,omitempty"`      // Binary name patterns, any of which may match
+	Paths      []string `json:"paths,omitempty"`      // Path patterns, any of which may match
+	Targets    []string `json:"targets,omitempty"`    // Patterns for the fully resolved path, any of which may match
+	Signatures []string `json:"signatures,omitempty"` // Byte strings, any of which the file must contain
+	Neighbours []string `json:"neighbours,omitempty"` // Paths that must all exist
+}
+
+// Provenance gives ghost binaries an origin story from a rule database: the
+// built-in rules plus the user's own rules in provenance.json in
+// snappoint's config directory, which are tried first
+type Provenance struct {
+	rules []ProvenanceRule
+	home  string
+}
+
+// NewProvenance creates a provenance analyzer with the built-in and user
+// rules. Unreadable user rules are reported as a warning and skipped.
+func NewProvenance() *Provenance {
+	var rules []ProvenanceRule
+
+	userFile := filepath.Join(snappointConfigDir(), "provenance.json")
+	if data, err := os.ReadFile(userFile); err == nil {
+		userRules, err := parseProvenanceRules(data)
+		if err != nil {
+			fmt.Printf("Warning: ignoring %s: %v\n", userFile, err)
+		}
+		rules = append(rules, userRules...)
+	}
+
+	builtin, _ := parseProvenanceRules(builtinProvenanceRules)
+
+	return &Provenance{
+		rules: append(rules, builtin...),
+		home:  system.GetHomeDir(),
+	}
+}
+
+// snappointConfigDir returns the directory snappoint reads user files from
+func snappointConfigDir() string {
+	return xdgDir("SNAPPOINT_CONFIG_DIR", "XDG_CONFIG_HOME", ".config", "snappoint")
+}
+
+// parseProvenanceRules decodes and validates a JSON list of rules
+func parseProvenanceRules(data []byte) ([]ProvenanceRule, error) {
+	var rules []ProvenanceRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		switch {
+		case rule.Story == "":
+			return nil, fmt.Errorf("rule %q has no story", rule.Name)
+		case rule.Confidence != "high" && rule.Confidence != "medium" && rule.Confidence != "low":
+			return nil, fmt.Errorf("rule %q has confidence %q, want high, medium or low", rule.Name, rule.Confidence)
+		case len(rule.Names) == 0 && len(rule.Paths) == 0 && len(rule.Targets) == 0:
+			return nil, fmt.Errorf("rule %q needs names, paths or targets", rule.Name)
+		}
+	}
+	return rules, nil
+}
+
+// Analyze sets the origin of every ghost the first matching rule recognizes
+func (p *Provenance) Analyze(result *scanner.ScanResult) {
+	// Every rule's signatures are searched for in one read of each file
+	var signatures []string
+	seen := make(map[string]bool)
+	for _, rule := range p.rules {
+		for _, signature := range rule.Signatures {
+			if !seen[signature] {
+				seen[signature] = true
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+
+	for _, ghost := range result.Ghosts {
+		if ghost.Origin != nil {
+			continue
+		}
+
+		contents := &fileSignatures{signatures: signatures}
+		for _, rule := range p.rules {
+			if p.matches(rule, ghost.Path, contents) {
+				ghost.Origin = &scanner.Origin{
+					Story:      p.expand(rule.Story, ghost.Path),
+					Confidence: rule.Confidence,
+				}
+				break
+			}
+		}
+	}
+}
+
+// matches reports whether every condition of a rule holds for path, whose
+// file's signatures are looked up in contents
+func (p *Provenance) matches(rule ProvenanceRule, path string, contents *fileSignatures) bool {
+	if len(rule.Names) > 0 && !p.matchAny(rule.Names, filepath.Base(path), path) {
+		return false
+	}
+	if len(rule.Paths) > 0 && !p.matchAny(rule.Paths, path, path) {
+		return false
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		resolved = path
+	}
+	if len(rule.Targets) > 0 && !p.matchAny(rule.Targets, resolved, path) {
+		return false
+	}
+
+	for _, neighbour := range rule.Neighbours {
+		if _, err := os.Stat(p.expand(neighbour, path)); err != nil {
+			return false
+		}
+	}
+
+	if len(rule.Signatures) > 0 && !contents.containsAny(resolved, rule.Signatures) {
+		return false
+	}
+	return true
+}
+
+// matchAny reports whether value matches any of the patterns, expanded for
+// the binary at path
+func (p *Provenance) matchAny(patterns []string, value, path string) bool {
+	for _, pattern := range patterns {
+		if globPattern(p.expand(pattern, path)).MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// expand substitutes ~/, {name} and {dir} for the binary at path
+func (p *Provenance) expand(pattern, path string) string {
+	if rest, ok := strings.CutPrefix(pattern, "~/"); ok {
+		pattern = filepath.Join(p.home, rest)
+	}
+	return strings.NewReplacer("{name}", filepath.Base(path), "{dir}", filepath.Dir(path)).Replace(pattern)
+}
+
+// globPattern compiles a path pattern where * matches within a segment and
+// ** matches across segments
+func globPattern(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			sb.WriteString("[^/]*")
+		case pattern[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+// fileSignatures records which of a set of signatures a file contains. The
+// file is read the first time it's asked about, and only then.
+type fileSignatures struct {
+	signatures []string
+	found      map[string]bool
+}
+
+// containsAny reports whether the file at path contains any of signatures,
+// which must be among those the fileSignatures was created with
+func (f *fileSignatures) containsAny(path string, signatures []string) bool {
+	if f.found == nil {
+		f.found = findSignatures(path, f.signatures)
+	}
+	for _, signature := range signatures {
+		if f.found[signature] {
+			return true
+		}
+	}
+	return false
+}
+
+// findSignatures returns which of the signatures a file contains, reading
+// it once in chunks that overlap so matches spanning two are found
+func findSignatures(path string, signatures []string) map[string]bool {
+	found := make(map[string]bool)
+	file, err := os.Open(path)
+	if err != nil {
+		return found
+	}
+	defer file.Close()
+
+	overlap := 0
+	for _, signature := range signatures {
+		overlap = max(overlap, len(signature)-1)
+	}
+
+	buf := make([]byte, 0, 1<<20+overlap)
+	chunk := make([]byte, 1<<20)
+	reader := io.LimitReader(file, maxSignatureScan)
+	for len(found) < len(signatures) {
+		n, err := reader.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		for _, signature := range signatures {
+			if !found[signature] && bytes.Contains(buf, []byte(signature)) {
+				found[signature] = true
+			}
+		}
+		if err != nil {
+			break
+		}
+		if len(buf) > overlap {
+			buf = append(buf[:0], buf[len(buf)-overlap:]...)
+		}
+	}
+	return found
+}
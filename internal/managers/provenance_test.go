@@ -0,0 +1,119 @@
+package managers
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+func TestBuiltinProvenanceRules(t *testing.T) {
+	rules, err := parseProvenanceRules(builtinProvenanceRules)
+	if err != nil {
+		t.Fatalf("Expected built-in rules to be valid, got %v", err)
+	}
+	if len(rules) == 0 {
+		t.Error("Expected built-in rules")
+	}
+}
+
+func TestGlobPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"/usr/local/bin/*", "/usr/local/bin/k3s", true},
+		{"/usr/local/bin/*", "/usr/local/bin/sub/k3s", false},
+		{"**/aws-cli/v2/*/bin/*", "/usr/local/aws-cli/v2/2.15.0/bin/aws", true},
+		{"**/google-cloud-sdk/bin/*", "/home/me/google-cloud-sdk/bin/gcloud", true},
+		{"*.AppImage", "Obsidian-1.5.3.AppImage", true},
+		{"/opt/app+1/*", "/opt/app+1/run", true},
+	}
+
+	for _, tt := range tests {
+		if got := globPattern(tt.pattern).MatchString(tt.path); got != tt.match {
+			t.Errorf("globPattern(%q) on %q: expected %v, got %v", tt.pattern, tt.path, tt.match, got)
+		}
+	}
+}
+
+func TestProvenanceAnalyze(t *testing.T) {
+	home := t.TempDir()
+
+	// A fly.io install: ~/.fly/bin/fly links to flyctl
+	writeFile(t, filepath.Join(home, ".fly", "bin", "flyctl"), "\x7fELF", 0755)
+	symlink(t, "flyctl", filepath.Join(home, ".fly", "bin", "fly"))
+
+	// A uv install by its cargo-dist installer
+	writeFile(t, filepath.Join(home, ".local", "bin", "uv"), "\x7fELF", 0755)
+	writeFile(t, filepath.Join(home, ".config", "uv", "uv-receipt.json"), "{}", 0644)
+
+	// A binary identified by its contents
+	writeFile(t, filepath.Join(home, "bin", "helm"), "\x7fELF...helm.sh/helm/v3/pkg...", 0755)
+	writeFile(t, filepath.Join(home, "bin", "mystery"), "\x7fELF", 0755)
+
+	rules, err := parseProvenanceRules([]byte(`[
+		{"name": "fly", "story": "installed by fly.io/install.sh", "confidence": "high", "paths": ["~/.fly/bin/*"], "targets": ["~/.fly/bin/flyctl"]},
+		{"name": "cargo-dist", "story": "installed by the {name} shell installer", "confidence": "high", "paths": ["~/.local/bin/*"], "neighbours": ["~/.config/{name}/{name}-receipt.json"]},
+		{"name": "helm", "story": "installed by get_helm.sh", "confidence": "medium", "names": ["helm"], "signatures": ["helm.sh/helm/v3"]}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := scanner.NewScanResult()
+	for _, path := range []string{".fly/bin/fly", ".local/bin/uv", "bin/helm", "bin/mystery"} {
+		result.AddBinary(&scanner.Binary{Name: filepath.Base(path), Path: filepath.Join(home, path), Manager: "manual"})
+	}
+
+	(&Provenance{rules: rules, home: home}).Analyze(result)
+
+	expected := map[string]string{
+		"fly":  "installed by fly.io/install.sh (high)",
+		"uv":   "installed by the uv shell installer (high)",
+		"helm": "installed by get_helm.sh (medium)",
+	}
+
+	for _, ghost := range result.Ghosts {
+		want, ok := expected[ghost.Name]
+		switch {
+		case !ok && ghost.Origin != nil:
+			t.Errorf("Expected no origin for %s, got %+v", ghost.Name, ghost.Origin)
+		case ok && ghost.Origin == nil:
+			t.Errorf("Expected origin for %s", ghost.Name)
+		case ok && ghost.Origin.Story+" ("+ghost.Origin.Confidence+")" != want:
+			t.Errorf("Expected %s origin %q, got %+v", ghost.Name, want, ghost.Origin)
+		}
+	}
+}
+
+func TestParseProvenanceRulesRejectsInvalidRules(t *testing.T) {
+	for _, data := range []string{
+		`[{"name": "a", "confidence": "high", "paths": ["/x"]}]`,
+		`[{"name": "a", "story": "s", "confidence": "certain", "paths": ["/x"]}]`,
+		`[{"name": "a", "story": "s", "confidence": "low"}]`,
+		`not json`,
+	} {
+		if _, err := parseProvenanceRules([]byte(data)); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+}
+
+func TestFindSignatures(t *testing.T) {
+	// One signature spans the boundary between the first two chunks
+	path := filepath.Join(t.TempDir(), "tool")
+	content := strings.Repeat("x", 1<<20-4) + "k8s.io/kubectl" + strings.Repeat("x", 100) + "go1.22.1"
+	writeFile(t, path, content, 0755)
+
+	found := findSignatures(path, []string{"k8s.io/kubectl", "go1.", "helm.sh/helm/v3"})
+	if !found["k8s.io/kubectl"] || !found["go1."] || found["helm.sh/helm/v3"] {
+		t.Errorf("Expected kubectl and go1. signatures only, got %v", found)
+	}
+
+	if found := findSignatures(filepath.Join(t.TempDir(), "missing"), []string{"go1."}); len(found) != 0 {
+		t.Errorf("Expected no signatures in a missing file, got %v", found)
+	}
+}
@@ -0,0 +1,292 @@
+package managers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// staleNightlyAge is how long a nightly toolchain can go unused before it's
+// flagged for removal
+const staleNightlyAge = 90 * 24 * time.Hour
+
+// rustupProxies lists the executables rustup installs into $CARGO_HOME/bin,
+// which dispatch to the active toolchain
+var rustupProxies = []string{
+	"rustc", "rustdoc", "cargo", "rust-lldb", "rust-gdb", "rust-gdbgui",
+	"rls", "cargo-clippy", "clippy-driver", "cargo-miri", "rustfmt",
+	"cargo-fmt", "rust-analyzer",
+}
+
+// Rustup implements the PackageManager interface for rustup toolchains
+type Rustup struct {
+	executor   system.CommandExecutor
+	rustupHome string
+	cargoHome  string
+	workDir    string
+	now        func() time.Time
+}
+
+// rustupToolchain is a single installed toolchain
+type rustupToolchain struct {
+	Name       string
+	Path       string
+	Version    string
+	Components []string
+}
+
+// NewRustup creates a new rustup package manager
+func NewRustup(executor system.CommandExecutor) *Rustup {
+	rustupHome := os.Getenv("RUSTUP_HOME")
+	if rustupHome == "" {
+		rustupHome = filepath.Join(system.GetHomeDir(), ".rustup")
+	}
+
+	cargoHome := os.Getenv("CARGO_HOME")
+	if cargoHome == "" {
+		cargoHome = filepath.Join(system.GetHomeDir(), ".cargo")
+	}
+
+	workDir, _ := os.Getwd()
+
+	return &Rustup{
+		executor:   executor,
+		rustupHome: rustupHome,
+		cargoHome:  cargoHome,
+		workDir:    workDir,
+		now:        time.Now,
+	}
+}
+
+// Name returns the name of the package manager
+func (r *Rustup) Name() string {
+	return "rustup"
+}
+
+// IsAvailable checks if rustup has a toolchains directory
+func (r *Rustup) IsAvailable(ctx context.Context) bool {
+	return isDir(filepath.Join(r.rustupHome, "toolchains"))
+}
+
+// Scan reports the rustup proxies on PATH, attributed to the active
+// toolchain, and every installed toolchain with its components and size
+func (r *Rustup) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	toolchains, err := r.readToolchains()
+	if err != nil {
+		return nil, err
+	}
+
+	settings, _ := readTOML(filepath.Join(r.rustupHome, "settings.toml"))
+	defaultToolchain := tomlString(settings[""]["default_toolchain"])
+
+	overrides := make(map[string]string)
+	for dir, toolchain := range settings["overrides"] {
+		overrides[dir] = tomlString(toolchain)
+	}
+
+	active := r.activeToolchain(toolchains, defaultToolchain, overrides)
+
+	var binaries []*scanner.Binary
+	if active != nil {
+		validator := system.NewFileValidator()
+		for _, proxy := range rustupProxies {
+			path := filepath.Join(r.cargoHome, "bin", proxy)
+			if !validator.IsBinaryExecutable(path) {
+				continue
+			}
+
+			binaries = append(binaries, &scanner.Binary{
+				Name:    proxy,
+				Path:    path,
+				Manager: r.Name(),
+				Version: active.Version,
+				Package: active.Name,
+			})
+		}
+	}
+
+	for _, toolchain := range toolchains {
+		binary := &scanner.Binary{
+			Name:     toolchain.Name,
+			Path:     toolchain.Path,
+			Manager:  r.Name(),
+			Version:  toolchain.Version,
+			Package:  "toolchain",
+			Inactive: toolchain != active,
+			Size:     system.DirSize(toolchain.Path),
+			Kind:     scanner.KindDirectory,
+		}
+
+		if matchesToolchain(toolchain.Name, defaultToolchain) {
+			binary.Notes = append(binary.Notes, "default toolchain")
+		}
+
+		var overridden []string
+		for dir, name := range overrides {
+			if matchesToolchain(toolchain.Name, name) {
+				overridden = append(overridden, dir)
+			}
+		}
+		sort.Strings(overridden)
+		for _, dir := range overridden {
+			binary.Notes = append(binary.Notes, "override for "+dir)
+		}
+
+		if len(toolchain.Components) > 0 {
+			binary.Notes = append(binary.Notes, "components: "+strings.Join(toolchain.Components, ", "))
+		}
+
+		if strings.HasPrefix(toolchain.Name, "nightly") && len(overridden) == 0 && !matchesToolchain(toolchain.Name, defaultToolchain) {
+			if unused := r.unusedFor(toolchain); unused >= staleNightlyAge {
+				binary.Notes = append(binary.Notes, fmt.Sprintf("nightly unused for %d days (rustup toolchain uninstall %s)", int(unused.Hours()/24), toolchain.Name))
+			}
+		}
+
+		binaries = append(binaries, binary)
+	}
+
+	return binaries, nil
+}
+
+// readToolchains lists installed toolchains with their rustc version and
+// installed components
+func (r *Rustup) readToolchains() ([]*rustupToolchain, error) {
+	toolchainsDir := filepath.Join(r.rustupHome, "toolchains")
+	entries, err := os.ReadDir(toolchainsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var toolchains []*rustupToolchain
+	for _, entry := range entries {
+		// Custom toolchains linked with `rustup toolchain link` are symlinks
+		path := filepath.Join(toolchainsDir, entry.Name())
+		if !isDir(path) {
+			continue
+		}
+
+		toolchain := &rustupToolchain{
+			Name: entry.Name(),
+			Path: path,
+		}
+
+		rustlib := filepath.Join(path, "lib", "rustlib")
+		if manifest, err := readTOML(filepath.Join(rustlib, "multirust-channel-manifest.toml")); err == nil {
+			// e.g. version = "1.75.0 (82e1608df 2023-12-21)"
+			if fields := strings.Fields(tomlString(manifest["pkg.rustc"]["version"])); len(fields) > 0 {
+				toolchain.Version = fields[0]
+			}
+		}
+
+		if data, err := os.ReadFile(filepath.Join(rustlib, "components")); err == nil {
+			triple := toolchainTriple(entry.Name())
+			for _, line := range strings.Fields(string(data)) {
+				toolchain.Components = append(toolchain.Components, strings.TrimSuffix(line, "-"+triple))
+			}
+		}
+
+		toolchains = append(toolchains, toolchain)
+	}
+
+	return toolchains, nil
+}
+
+// activeToolchain resolves the toolchain the proxies run, in rustup's order:
+// RUSTUP_TOOLCHAIN, a directory override or rust-toolchain file in the
+// working directory or its parents, then the default
+func (r *Rustup) activeToolchain(toolchains []*rustupToolchain, defaultToolchain string, overrides map[string]string) *rustupToolchain {
+	find := func(name string) *rustupToolchain {
+		for _, toolchain := range toolchains {
+			if matchesToolchain(toolchain.Name, name) {
+				return toolchain
+			}
+		}
+		return nil
+	}
+
+	if name := os.Getenv("RUSTUP_TOOLCHAIN"); name != "" {
+		return find(name)
+	}
+
+	for dir := r.workDir; dir != ""; {
+		if name, ok := overrides[dir]; ok {
+			return find(name)
+		}
+		if name := readToolchainFile(dir); name != "" {
+			return find(name)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return find(defaultToolchain)
+}
+
+// unusedFor returns how long ago a toolchain's rustc or cargo was last run
+func (r *Rustup) unusedFor(toolchain *rustupToolchain) time.Duration {
+	var lastUsed time.Time
+	for _, name := range []string{"rustc", "cargo"} {
+		if accessed, err := system.LastAccessTime(filepath.Join(toolchain.Path, "bin", name)); err == nil && accessed.After(lastUsed) {
+			lastUsed = accessed
+		}
+	}
+
+	if lastUsed.IsZero() {
+		return 0
+	}
+	return r.now().Sub(lastUsed)
+}
+
+// readToolchainFile reads the channel from a rust-toolchain or
+// rust-toolchain.toml file in dir
+func readToolchainFile(dir string) string {
+	if tables, err := readTOML(filepath.Join(dir, "rust-toolchain.toml")); err == nil {
+		return tomlString(tables["toolchain"]["channel"])
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "rust-toolchain"))
+	if err != nil {
+		return ""
+	}
+
+	// The legacy format is a bare channel name; newer files are TOML
+	if tables, err := readTOML(filepath.Join(dir, "rust-toolchain")); err == nil {
+		if channel := tomlString(tables["toolchain"]["channel"]); channel != "" {
+			return channel
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// matchesToolchain reports whether an installed toolchain such as
+// "stable-x86_64-unknown-linux-gnu" satisfies a name such as "stable"
+func matchesToolchain(installed, name string) bool {
+	if name == "" {
+		return false
+	}
+	return installed == name || strings.TrimSuffix(installed, "-"+toolchainTriple(installed)) == name
+}
+
+// toolchainTriple returns the host triple at the end of a toolchain name,
+// e.g. "x86_64-unknown-linux-gnu" for "nightly-2024-01-01-x86_64-unknown-linux-gnu"
+func toolchainTriple(name string) string {
+	parts := strings.Split(name, "-")
+	for i, part := range parts {
+		if strings.HasPrefix(part, "x86_64") || strings.HasPrefix(part, "aarch64") ||
+			strings.HasPrefix(part, "i686") || strings.HasPrefix(part, "arm") {
+			return strings.Join(parts[i:], "-")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRustupScan(t *testing.T) {
+	rustupHome := t.TempDir()
+	cargoHome := t.TempDir()
+	t.Setenv("RUSTUP_TOOLCHAIN", "")
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	triple := "x86_64-unknown-linux-gnu"
+
+	for name, version := range map[string]string{
+		"stable-" + triple:             "1.79.0",
+		"nightly-2025-01-10-" + triple: "1.86.0-nightly",
+	} {
+		toolchain := filepath.Join(rustupHome, "toolchains", name)
+		writeFile(t, filepath.Join(toolchain, "bin", "rustc"), "", 0755)
+		writeFile(t, filepath.Join(toolchain, "lib", "rustlib", "components"), "rustc-"+triple+"\ncargo-"+triple+"\nclippy-preview-"+triple+"\n", 0644)
+		writeFile(t, filepath.Join(toolchain, "lib", "rustlib", "multirust-channel-manifest.toml"), "[pkg.rustc]\nversion = \""+version+" (abc123 2025-01-01)\"\n", 0644)
+	}
+
+	nightlyRustc := filepath.Join(rustupHome, "toolchains", "nightly-2025-01-10-"+triple, "bin", "rustc")
+	lastUsed := now.Add(-200 * 24 * time.Hour)
+	if err := os.Chtimes(nightlyRustc, lastUsed, lastUsed); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(rustupHome, "settings.toml"), "default_toolchain = \"stable-"+triple+"\"\nprofile = \"default\"\n\n[overrides]\n\"/srv/legacy\" = \"stable\"\n", 0644)
+	writeFile(t, filepath.Join(cargoHome, "bin", "cargo"), "", 0755)
+
+	rustup := &Rustup{rustupHome: rustupHome, cargoHome: cargoHome, workDir: t.TempDir(), now: func() time.Time { return now }}
+	binaries, err := rustup.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, binary := range binaries {
+		notes := strings.Join(binary.Notes, "; ")
+		switch binary.Name {
+		case "cargo":
+			if binary.Version != "1.79.0" || binary.Package != "stable-"+triple {
+				t.Errorf("Expected cargo proxy on stable, got %s (%s)", binary, binary.Package)
+			}
+		case "stable-" + triple:
+			if binary.Inactive || !strings.Contains(notes, "default toolchain") || !strings.Contains(notes, "override for /srv/legacy") {
+				t.Errorf("Unexpected stable toolchain: %s %s", binary, notes)
+			}
+			if !strings.Contains(notes, "components: rustc, cargo, clippy-preview") {
+				t.Errorf("Expected components without host triple, got %s", notes)
+			}
+		case "nightly-2025-01-10-" + triple:
+			if !binary.Inactive || !strings.Contains(notes, "nightly unused for 200 days") {
+				t.Errorf("Expected stale nightly, got %s %s", binary, notes)
+			}
+		default:
+			t.Errorf("Unexpected binary: %s", binary)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package managers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// SDKMAN implements the PackageManager interface for SDKMAN candidates such
+// as Java, Gradle, Maven and Kotlin
+type SDKMAN struct {
+	executor system.CommandExecutor
+	dir      string
+}
+
+// NewSDKMAN creates a new SDKMAN package manager
+func NewSDKMAN(executor system.CommandExecutor) *SDKMAN {
+	dir := os.Getenv("SDKMAN_DIR")
+	if dir == "" {
+		dir = filepath.Join(system.GetHomeDir(), ".sdkman")
+	}
+
+	return &SDKMAN{
+		executor: executor,
+		dir:      dir,
+	}
+}
+
+// Name returns the name of the package manager
+func (s *SDKMAN) Name() string {
+	return "sdkman"
+}
+
+// IsAvailable checks if SDKMAN has a candidates directory
+func (s *SDKMAN) IsAvailable(ctx context.Context) bool {
+	return isDir(filepath.Join(s.dir, "candidates"))
+}
+
+// Scan reports the executables of each candidate's current version and
+// every other installed version with its disk usage. The current Java is
+// checked against JAVA_HOME and the java that wins on PATH.
+func (s *SDKMAN) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	candidatesDir := filepath.Join(s.dir, "candidates")
+	candidates, err := os.ReadDir(candidatesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var binaries []*scanner.Binary
+	validator := system.NewFileValidator()
+
+	for _, candidate := range candidates {
+		if !candidate.IsDir() {
+			continue
+		}
+
+		candidateDir := filepath.Join(candidatesDir, candidate.Name())
+		current := currentSDKMANVersion(candidateDir)
+
+		for _, version := range readVersionDirs(candidateDir, true) {
+			if version == "current" {
+				continue
+			}
+
+			if version != current {
+				versionDir := filepath.Join(candidateDir, version)
+				binaries = append(binaries, &scanner.Binary{
+					Name:     candidate.Name(),
+					Path:     versionDir,
+					Manager:  s.Name(),
+					Version:  version,
+					Package:  candidate.Name(),
+					Inactive: true,
+					Size:     system.DirSize(versionDir),
+					Kind:     scanner.KindDirectory,
+				})
+				continue
+			}
+
+			// The current version is put on PATH through the current symlink
+			binDir := filepath.Join(candidateDir, "current", "bin")
+			inPath := system.IsInPATH(binDir)
+
+			names := validator.ListExecutables(binDir)
+			sort.Strings(names)
+			for _, name := range names {
+				binary := &scanner.Binary{
+					Name:     name,
+					Path:     filepath.Join(binDir, name),
+					Manager:  s.Name(),
+					Version:  version,
+					Package:  candidate.Name(),
+					Inactive: !inPath,
+				}
+
+				if candidate.Name() == "java" && name == "java" {
+					binary.Notes = javaConsistencyNotes(filepath.Join(candidateDir, "current"), version)
+				}
+
+				binaries = append(binaries, binary)
+			}
+		}
+	}
+
+	return binaries, nil
+}
+
+// currentSDKMANVersion returns the version a candidate's current symlink
+// points at
+func currentSDKMANVersion(candidateDir string) string {
+	target, err := os.Readlink(filepath.Join(candidateDir, "current"))
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(target)
+}
+
+// javaConsistencyNotes compares SDKMAN's current Java with JAVA_HOME and
+// with the java that wins on PATH. Mismatches make builds such as Android's
+// use a different JDK than the java command does.
+func javaConsistencyNotes(currentHome, version string) []string {
+	var notes []string
+
+	sdkHome := resolvePath(currentHome)
+
+	if javaHome := os.Getenv("JAVA_HOME"); javaHome != "" && resolvePath(javaHome) != sdkHome {
+		notes = append(notes, fmt.Sprintf("JAVA_HOME is %s, not SDKMAN's current java %s", javaHome, version))
+	}
+
+	if winner := system.LookPath("java"); winner != "" {
+		// The JDK home is the parent of the bin directory java resolves into
+		if resolvedHome := filepath.Dir(filepath.Dir(resolvePath(winner))); resolvedHome != sdkHome {
+			notes = append(notes, fmt.Sprintf("java on PATH is %s (%s), not SDKMAN's current java %s", winner, resolvedHome, version))
+		}
+	}
+
+	return notes
+}
+
+// resolvePath resolves symlinks in path, returning it cleaned but unchanged
+// when it can't be resolved
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
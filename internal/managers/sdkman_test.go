@@ -0,0 +1,102 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+// writeSDKMANCandidates creates a candidates directory with two Javas, the
+// newer one current, and a Gradle
+func writeSDKMANCandidates(t *testing.T, dir string) string {
+	t.Helper()
+	java := filepath.Join(dir, "candidates", "java")
+	writeFile(t, filepath.Join(java, "17.0.10-tem", "bin", "java"), "\x7fELF", 0755)
+	writeFile(t, filepath.Join(java, "21.0.2-tem", "bin", "java"), "\x7fELF", 0755)
+	writeFile(t, filepath.Join(java, "21.0.2-tem", "bin", "javac"), "\x7fELF", 0755)
+	symlink(t, filepath.Join(java, "21.0.2-tem"), filepath.Join(java, "current"))
+
+	gradle := filepath.Join(dir, "candidates", "gradle")
+	writeFile(t, filepath.Join(gradle, "8.6", "bin", "gradle"), "#!/bin/sh\n", 0755)
+	symlink(t, "8.6", filepath.Join(gradle, "current"))
+	return java
+}
+
+func TestSDKMANScan(t *testing.T) {
+	dir := t.TempDir()
+	java := writeSDKMANCandidates(t, dir)
+
+	t.Setenv("JAVA_HOME", filepath.Join(java, "current"))
+	t.Setenv("PATH", strings.Join([]string{
+		filepath.Join(java, "current", "bin"),
+		filepath.Join(dir, "candidates", "gradle", "current", "bin"),
+	}, string(os.PathListSeparator)))
+
+	sdkman := &SDKMAN{executor: &fakeExecutor{}, dir: dir}
+	binaries, err := sdkman.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"java":   "21.0.2-tem",
+		"javac":  "21.0.2-tem",
+		"gradle": "8.6",
+	}
+	for name, version := range expected {
+		binary := findBinaryVersion(binaries, name, version)
+		if binary == nil || binary.Inactive {
+			t.Errorf("Expected %s %s to be active, got %+v", name, version, binary)
+		}
+	}
+	if notes := findBinaryVersion(binaries, "java", "21.0.2-tem").Notes; len(notes) != 0 {
+		t.Errorf("Expected no notes when JAVA_HOME and PATH agree with SDKMAN, got %v", notes)
+	}
+
+	old := findBinaryVersion(binaries, "java", "17.0.10-tem")
+	if old == nil || !old.Inactive || old.Kind != scanner.KindDirectory || old.Path != filepath.Join(java, "17.0.10-tem") || old.Size == 0 {
+		t.Errorf("Expected 17.0.10-tem to be reported as an inactive install, got %+v", old)
+	}
+}
+
+func TestSDKMANJavaConsistency(t *testing.T) {
+	dir := t.TempDir()
+	java := writeSDKMANCandidates(t, dir)
+
+	// A system JDK wins on PATH, and JAVA_HOME points at another version
+	systemJDK := filepath.Join(t.TempDir(), "jvm", "openjdk-11")
+	writeFile(t, filepath.Join(systemJDK, "bin", "java"), "\x7fELF", 0755)
+	usrBin := t.TempDir()
+	symlink(t, filepath.Join(systemJDK, "bin", "java"), filepath.Join(usrBin, "java"))
+
+	t.Setenv("JAVA_HOME", filepath.Join(java, "17.0.10-tem"))
+	t.Setenv("PATH", strings.Join([]string{usrBin, filepath.Join(java, "current", "bin")}, string(os.PathListSeparator)))
+
+	sdkman := &SDKMAN{executor: &fakeExecutor{}, dir: dir}
+	binaries, err := sdkman.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	javaBinary := findBinaryVersion(binaries, "java", "21.0.2-tem")
+	if javaBinary == nil {
+		t.Fatal("Expected SDKMAN's current java to be reported")
+	}
+
+	expected := []string{
+		"JAVA_HOME is " + filepath.Join(java, "17.0.10-tem") + ", not SDKMAN's current java 21.0.2-tem",
+		"java on PATH is " + filepath.Join(usrBin, "java") + " (" + resolvePath(systemJDK) + "), not SDKMAN's current java 21.0.2-tem",
+	}
+	if len(javaBinary.Notes) != len(expected) {
+		t.Fatalf("Expected notes %v, got %v", expected, javaBinary.Notes)
+	}
+	for i, note := range expected {
+		if javaBinary.Notes[i] != note {
+			t.Errorf("Expected note %q, got %q", note, javaBinary.Notes[i])
+		}
+	}
+}
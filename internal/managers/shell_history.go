@@ -0,0 +1,346 @@
+package managers
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// commandSeparatorPattern splits a history line into commands, keeping
+// pipelines together so `curl … | sh` stays one command
+var commandSeparatorPattern = regexp.MustCompile(`\s*(?:&&|\|\||;)\s*`)
+
+// makeInstallLookback is how many earlier history entries are searched for
+// the cd that preceded a make install
+const makeInstallLookback = 20
+
+// confidenceRank orders origin confidence levels
+var confidenceRank = map[string]int{"low": 1, "medium": 2, "high": 3}
+
+// historyEntry is one command from a shell history file
+type historyEntry struct {
+	Command string
+	Time    time.Time // Zero when the history has no timestamps
+}
+
+// historyMatch is a command that likely installed a ghost
+type historyMatch struct {
+	Entry      historyEntry
+	Confidence string
+}
+
+// ShellHistory attributes ghost binaries to the commands in bash, zsh and
+// fish history that most likely installed them, such as `curl … | sh`, a
+// cp or install into the ghost's directory, or a make install
+type ShellHistory struct {
+	files []historyFile
+	home  string // Expanded for ~ and $HOME in commands
+}
+
+// historyFile is a history file and the shell whose format it's in
+type historyFile struct {
+	Path  string
+	Shell string
+}
+
+// NewShellHistory creates a history analyzer for the current user's bash,
+// zsh and fish history files
+func NewShellHistory() *ShellHistory {
+	home := system.GetHomeDir()
+
+	zshDir := os.Getenv("ZDOTDIR")
+	if zshDir == "" {
+		zshDir = home
+	}
+
+	return &ShellHistory{
+		files: []historyFile{
+			{Path: filepath.Join(home, ".bash_history"), Shell: "bash"},
+			{Path: filepath.Join(zshDir, ".zsh_history"), Shell: "zsh"},
+			{Path: filepath.Join(zshDir, ".zhistory"), Shell: "zsh"},
+			{Path: filepath.Join(xdgDir("", "XDG_DATA_HOME", filepath.Join(".local", "share"), "fish"), "fish_history"), Shell: "fish"},
+		},
+		home: home,
+	}
+}
+
+// Analyze records the best matching history command on each ghost's
+// origin, creating one when no provenance rule recognized the ghost
+func (h *ShellHistory) Analyze(result *scanner.ScanResult) {
+	if len(result.Ghosts) == 0 {
+		return
+	}
+
+	var histories [][]historyEntry
+	for _, file := range h.files {
+		histories = append(histories, readHistory(file.Path, file.Shell))
+	}
+
+	for _, ghost := range result.Ghosts {
+		var match *historyMatch
+		for _, entries := range histories {
+			if m := matchHistory(entries, ghost.Name, ghost.Path, h.home); m != nil && m.betterThan(match) {
+				match = m
+			}
+		}
+		if match == nil {
+			continue
+		}
+
+		if ghost.Origin == nil {
+			ghost.Origin = &scanner.Origin{
+				Story:      "installed by a command in your shell history",
+				Confidence: match.Confidence,
+			}
+		}
+		ghost.Origin.Command = match.Entry.Command
+		ghost.Origin.RunAt = match.Entry.Time
+	}
+}
+
+// readHistory reads the commands of one history file
+func readHistory(path, shell string) []historyEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	switch shell {
+	case "zsh":
+		return parseZshHistory(unmetafyZsh(data))
+	case "fish":
+		return parseFishHistory(string(data))
+	}
+	return parseBashHistory(string(data))
+}
+
+// parseBashHistory reads bash history, where HISTTIMEFORMAT adds a
+// "#<unix time>" line before each command
+func parseBashHistory(data string) []historyEntry {
+	var entries []historyEntry
+	var when time.Time
+
+	for _, line := range strings.Split(data, "\n") {
+		if stamp, ok := strings.CutPrefix(line, "#"); ok {
+			if seconds, err := strconv.ParseInt(stamp, 10, 64); err == nil {
+				when = time.Unix(seconds, 0)
+				continue
+			}
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		entries = append(entries, historyEntry{Command: line, Time: when})
+		when = time.Time{}
+	}
+	return entries
+}
+
+// parseZshHistory reads zsh history in the plain format or the
+// EXTENDED_HISTORY format, ": <start>:<duration>;<command>". Commands
+// spanning several lines end each line but the last with a backslash.
+func parseZshHistory(data string) []historyEntry {
+	var entries []historyEntry
+	lines := strings.Split(data, "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, "\\") + "\n" + lines[i]
+		}
+
+		entry := historyEntry{Command: line}
+		if rest, ok := strings.CutPrefix(line, ": "); ok {
+			if meta, command, ok := strings.Cut(rest, ";"); ok {
+				start, _, _ := strings.Cut(meta, ":")
+				if seconds, err := strconv.ParseInt(strings.TrimSpace(start), 10, 64); err == nil {
+					entry = historyEntry{Command: command, Time: time.Unix(seconds, 0)}
+				}
+			}
+		}
+
+		if strings.TrimSpace(entry.Command) != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// unmetafyZsh undoes zsh's escaping of bytes above 0x80 in history files:
+// each is written as 0x83 followed by the byte XOR 32
+func unmetafyZsh(data []byte) string {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] == 0x83 && i+1 < len(data) {
+			i++
+			out = append(out, data[i]^32)
+			continue
+		}
+		out = append(out, data[i])
+	}
+	return string(out)
+}
+
+// parseFishHistory reads fish's YAML-like history, a list of
+// "- cmd: <command>" items each followed by "  when: <unix time>"
+func parseFishHistory(data string) []historyEntry {
+	var entries []historyEntry
+	for _, line := range strings.Split(data, "\n") {
+		if command, ok := strings.CutPrefix(line, "- cmd: "); ok {
+			command = strings.NewReplacer(`\n`, "\n", `\\`, `\`).Replace(command)
+			entries = append(entries, historyEntry{Command: command})
+			continue
+		}
+
+		if stamp, ok := strings.CutPrefix(strings.TrimSpace(line), "when: "); ok && len(entries) > 0 {
+			if seconds, err := strconv.ParseInt(stamp, 10, 64); err == nil {
+				entries[len(entries)-1].Time = time.Unix(seconds, 0)
+			}
+		}
+	}
+	return entries
+}
+
+// matchHistory finds the command that most likely installed the ghost at
+// path: the most confident match, then the most recent
+func matchHistory(entries []historyEntry, name, path, home string) *historyMatch {
+	var best *historyMatch
+	for i, entry := range entries {
+		confidence := ""
+		for _, command := range commandSeparatorPattern.Split(entry.Command, -1) {
+			if c := matchInstallCommand(command, name, path, home); confidenceRank[c] > confidenceRank[confidence] {
+				confidence = c
+			}
+		}
+
+		// make install only names its project through the directory it ran in
+		if confidence == "" && strings.Contains(entry.Command, "make install") && cdIntoProject(entries[max(0, i-makeInstallLookback):i], name) {
+			confidence = "low"
+		}
+
+		if confidence == "" {
+			continue
+		}
+		if match := (&historyMatch{Entry: entry, Confidence: confidence}); match.betterThan(best) {
+			best = match
+		}
+	}
+	return best
+}
+
+// betterThan reports whether m is more confident than other, or as
+// confident and no older
+func (m *historyMatch) betterThan(other *historyMatch) bool {
+	if other == nil {
+		return true
+	}
+	if confidenceRank[m.Confidence] != confidenceRank[other.Confidence] {
+		return confidenceRank[m.Confidence] > confidenceRank[other.Confidence]
+	}
+	return !m.Entry.Time.Before(other.Entry.Time)
+}
+
+// matchInstallCommand rates how strongly a single command looks like it
+// installed the ghost, returning "" when it doesn't. Arguments starting with
+// ~, $HOME or ${HOME} are expanded with home before being compared.
+func matchInstallCommand(command, name, path, home string) string {
+	fields := strings.Fields(command)
+	for len(fields) > 0 && (fields[0] == "sudo" || fields[0] == "doas") {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+
+	dir := filepath.Dir(path)
+	mentionsPath := false
+	mentionsName := false
+	for _, field := range fields {
+		field = expandHome(strings.Trim(field, `'"`), home)
+		switch {
+		case field == path:
+			mentionsPath = true
+		case filepath.Base(field) == name:
+			mentionsName = true
+		}
+	}
+
+	switch fields[0] {
+	case "cp", "mv", "install", "ln":
+		// Copied to the ghost's path, or a file named like it into its directory
+		last := strings.TrimSuffix(expandHome(strings.Trim(fields[len(fields)-1], `'"`), home), "/")
+		if mentionsPath || last == dir && mentionsName {
+			return "high"
+		}
+	case "chmod":
+		if mentionsPath {
+			return "high"
+		}
+	case "curl", "wget":
+		if mentionsPath {
+			return "high"
+		}
+		// curl … | sh, with the script or download named after the tool
+		if mentionsWord(strings.ToLower(command), strings.ToLower(name)) {
+			if strings.Contains(command, "|") {
+				return "medium"
+			}
+			return "low"
+		}
+	}
+	return ""
+}
+
+// expandHome replaces a leading ~, $HOME or ${HOME} in a command argument
+// with the home directory, as the shell did when it ran the command
+func expandHome(field, home string) string {
+	if home == "" {
+		return field
+	}
+	for _, prefix := range []string{"~", "${HOME}", "$HOME"} {
+		if rest, ok := strings.CutPrefix(field, prefix); ok && (rest == "" || rest[0] == '/') {
+			return home + rest
+		}
+	}
+	return field
+}
+
+// cdIntoProject reports whether any of the entries changed into a
+// directory named after the ghost, as a checkout built with make install is
+func cdIntoProject(entries []historyEntry, name string) bool {
+	for _, entry := range entries {
+		fields := strings.Fields(entry.Command)
+		if len(fields) >= 2 && fields[0] == "cd" && strings.Contains(strings.ToLower(filepath.Base(fields[1])), strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsWord reports whether word appears in text other than as part of
+// a longer name, so "go" doesn't match "google"
+func mentionsWord(text, word string) bool {
+	isNameByte := func(b byte) bool {
+		return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b == '_'
+	}
+
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], word)
+		if idx < 0 {
+			return false
+		}
+		start, end := offset+idx, offset+idx+len(word)
+		if (start == 0 || !isNameByte(text[start-1])) && (end == len(text) || !isNameByte(text[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+}
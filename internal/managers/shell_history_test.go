@@ -0,0 +1,123 @@
+package managers
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+func TestParseHistoryFormats(t *testing.T) {
+	zsh := parseZshHistory(": 1700000000:0;curl -fsSL https://example.com/install.sh | sh\n: 1700000100:2;echo one \\\ntwo\nplain command\n")
+	if len(zsh) != 3 {
+		t.Fatalf("Expected 3 zsh entries, got %d", len(zsh))
+	}
+	if zsh[0].Command != "curl -fsSL https://example.com/install.sh | sh" || !zsh[0].Time.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Unexpected extended zsh entry: %+v", zsh[0])
+	}
+	if zsh[1].Command != "echo one \ntwo" {
+		t.Errorf("Expected multi-line zsh command, got %q", zsh[1].Command)
+	}
+	if zsh[2].Command != "plain command" || !zsh[2].Time.IsZero() {
+		t.Errorf("Unexpected plain zsh entry: %+v", zsh[2])
+	}
+
+	bash := parseBashHistory("#1700000000\nsudo make install\nls\n")
+	if len(bash) != 2 || !bash[0].Time.Equal(time.Unix(1700000000, 0)) || !bash[1].Time.IsZero() {
+		t.Errorf("Unexpected bash entries: %+v", bash)
+	}
+
+	fish := parseFishHistory("- cmd: chmod +x ~/bin/tool\n  when: 1700000000\n- cmd: ls\n  when: 1700000001\n  paths:\n    - ~/bin\n")
+	if len(fish) != 2 || fish[0].Command != "chmod +x ~/bin/tool" || !fish[1].Time.Equal(time.Unix(1700000001, 0)) {
+		t.Errorf("Unexpected fish entries: %+v", fish)
+	}
+
+	if got := unmetafyZsh([]byte{'c', 'a', 'f', 0xc3, 0x83, 0xa9 ^ 32}); got != "café" {
+		t.Errorf("Expected unmetafied café, got %q", got)
+	}
+}
+
+func TestMatchInstallCommand(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"sudo install -m 755 ./tool /usr/local/bin/tool", "high"},
+		{"sudo cp build/tool /usr/local/bin/", "high"},
+		{"mv tool /usr/local/bin", "high"},
+		{"chmod +x /usr/local/bin/tool", "high"},
+		{"curl -Lo /usr/local/bin/tool https://example.com/tool", "high"},
+		{"curl -fsSL https://tool.dev/install.sh | sh", "medium"},
+		{"wget https://example.com/releases/tool-linux-amd64.tar.gz", "low"},
+		{"curl -fsSL https://toolbox.dev/install.sh | sh", ""},
+		{"cp tool ~/backup/", ""},
+		{"brew install tool", ""},
+	}
+
+	for _, tt := range tests {
+		if got := matchInstallCommand(tt.command, "tool", "/usr/local/bin/tool", "/home/u"); got != tt.expected {
+			t.Errorf("matchInstallCommand(%q): expected %q, got %q", tt.command, tt.expected, got)
+		}
+	}
+
+	// Per-user bin directories are usually written relative to the home
+	homeTests := []struct {
+		command  string
+		path     string
+		expected string
+	}{
+		{"mv tool ~/.local/bin/", "/home/u/.local/bin/tool", "high"},
+		{"chmod +x ~/bin/tool", "/home/u/bin/tool", "high"},
+		{`cp build/tool "$HOME/.local/bin"`, "/home/u/.local/bin/tool", "high"},
+		{"install -m 755 tool ${HOME}/bin/tool", "/home/u/bin/tool", "high"},
+		{"curl -Lo ~/bin/tool https://example.com/tool", "/home/u/bin/tool", "high"},
+		{"mv tool ~/.local/bin/", "/usr/local/bin/tool", ""},
+		{"cp tool $HOMER/bin/", "/home/u/bin/tool", ""},
+	}
+	for _, tt := range homeTests {
+		if got := matchInstallCommand(tt.command, "tool", tt.path, "/home/u"); got != tt.expected {
+			t.Errorf("matchInstallCommand(%q, %s): expected %q, got %q", tt.command, tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestShellHistoryAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	zshHistory := filepath.Join(dir, ".zsh_history")
+	bashHistory := filepath.Join(dir, ".bash_history")
+
+	writeFile(t, zshHistory, ": 1700000000:0;curl -fsSL https://starship.rs/install.sh | sh\n: 1710000000:0;cd ~/src/jq-1.7 && ./configure\n: 1710000100:0;sudo make install\n", 0600)
+	writeFile(t, bashHistory, "#1690000000\nsudo cp starship /usr/local/bin/\n", 0600)
+	fishHistory := filepath.Join(dir, "fish_history")
+	writeFile(t, fishHistory, "- cmd: chmod +x ~/bin/tool\n  when: 1700000000\n", 0600)
+
+	result := scanner.NewScanResult()
+	starship := &scanner.Binary{Name: "starship", Path: "/usr/local/bin/starship", Manager: "manual"}
+	jq := &scanner.Binary{Name: "jq", Path: "/usr/local/bin/jq", Manager: "manual", Origin: &scanner.Origin{Story: "built from source", Confidence: "medium"}}
+	unknown := &scanner.Binary{Name: "mystery", Path: "/usr/local/bin/mystery", Manager: "manual"}
+	tool := &scanner.Binary{Name: "tool", Path: "/home/u/bin/tool", Manager: "manual"}
+	result.AddBinary(tool)
+	result.AddBinary(starship)
+	result.AddBinary(jq)
+	result.AddBinary(unknown)
+
+	history := &ShellHistory{
+		files: []historyFile{{Path: zshHistory, Shell: "zsh"}, {Path: bashHistory, Shell: "bash"}, {Path: fishHistory, Shell: "fish"}},
+		home:  "/home/u",
+	}
+	history.Analyze(result)
+
+	if starship.Origin == nil || starship.Origin.Command != "sudo cp starship /usr/local/bin/" || starship.Origin.Confidence != "high" {
+		t.Errorf("Expected the cp into /usr/local/bin to win, got %+v", starship.Origin)
+	}
+	if jq.Origin.Story != "built from source" || jq.Origin.Command != "sudo make install" || !jq.Origin.RunAt.Equal(time.Unix(1710000100, 0)) {
+		t.Errorf("Expected make install to annotate the existing origin, got %+v", jq.Origin)
+	}
+	if tool.Origin == nil || tool.Origin.Command != "chmod +x ~/bin/tool" || tool.Origin.Confidence != "high" {
+		t.Errorf("Expected the fish chmod of ~/bin/tool to match, got %+v", tool.Origin)
+	}
+	if unknown.Origin != nil {
+		t.Errorf("Expected no origin for mystery, got %+v", unknown.Origin)
+	}
+}
@@ -0,0 +1,196 @@
+package managers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// ShimEnvConfig describes one member of the rbenv family of version
+// managers. They all keep versions in <root>/versions/<version>, put shims
+// in a single directory, and select a version from an environment variable,
+// a local version file found from the working directory upwards, or the
+// global <root>/version file.
+type ShimEnvConfig struct {
+	Name        string // Manager name, e.g. "rbenv"
+	RootEnv     string // Variable overriding the root, e.g. "RBENV_ROOT"
+	DefaultRoot string // Root relative to $HOME, e.g. ".rbenv"
+	VersionEnv  string // Variable selecting a version, e.g. "RBENV_VERSION"
+	LocalFile   string // Per-directory version file, e.g. ".ruby-version"
+	ShimsDir    string // Shims directory relative to the root
+	Tool        string // Prefix some version files carry, e.g. "ruby" in "ruby-3.3.0"
+}
+
+// DefaultShimEnvs lists the rbenv-style version managers scanned by default
+var DefaultShimEnvs = []ShimEnvConfig{
+	{Name: "rbenv", RootEnv: "RBENV_ROOT", DefaultRoot: ".rbenv", VersionEnv: "RBENV_VERSION", LocalFile: ".ruby-version", ShimsDir: "shims", Tool: "ruby"},
+	{Name: "pyenv", RootEnv: "PYENV_ROOT", DefaultRoot: ".pyenv", VersionEnv: "PYENV_VERSION", LocalFile: ".python-version", ShimsDir: "shims", Tool: "python"},
+	{Name: "goenv", RootEnv: "GOENV_ROOT", DefaultRoot: ".goenv", VersionEnv: "GOENV_VERSION", LocalFile: ".go-version", ShimsDir: "shims", Tool: "go"},
+	{Name: "nodenv", RootEnv: "NODENV_ROOT", DefaultRoot: ".nodenv", VersionEnv: "NODENV_VERSION", LocalFile: ".node-version", ShimsDir: "shims", Tool: "node"},
+	{Name: "jenv", RootEnv: "JENV_ROOT", DefaultRoot: ".jenv", VersionEnv: "JENV_VERSION", LocalFile: ".java-version", ShimsDir: "shims", Tool: "java"},
+	// tfenv has no shims directory; its bin directory holds a terraform wrapper
+	{Name: "tfenv", RootEnv: "TFENV_ROOT", DefaultRoot: ".tfenv", VersionEnv: "TFENV_TERRAFORM_VERSION", LocalFile: ".terraform-version", ShimsDir: "bin", Tool: "terraform"},
+}
+
+// ShimEnv implements the PackageManager interface for any rbenv-style
+// version manager described by a ShimEnvConfig
+type ShimEnv struct {
+	executor system.CommandExecutor
+	config   ShimEnvConfig
+	root     string
+	workDir  string
+}
+
+// NewShimEnv creates a package manager for one rbenv-style version manager
+func NewShimEnv(executor system.CommandExecutor, config ShimEnvConfig) *ShimEnv {
+	root := os.Getenv(config.RootEnv)
+	if root == "" {
+		root = filepath.Join(system.GetHomeDir(), config.DefaultRoot)
+	}
+
+	workDir, _ := os.Getwd()
+
+	return &ShimEnv{
+		executor: executor,
+		config:   config,
+		root:     root,
+		workDir:  workDir,
+	}
+}
+
+// NewShimEnvs creates a package manager for each of DefaultShimEnvs
+func NewShimEnvs(executor system.CommandExecutor) []*ShimEnv {
+	envs := make([]*ShimEnv, 0, len(DefaultShimEnvs))
+	for _, config := range DefaultShimEnvs {
+		envs = append(envs, NewShimEnv(executor, config))
+	}
+	return envs
+}
+
+// Name returns the name of the package manager
+func (s *ShimEnv) Name() string {
+	return s.config.Name
+}
+
+// IsAvailable checks if the manager has a versions directory
+func (s *ShimEnv) IsAvailable(ctx context.Context) bool {
+	return isDir(filepath.Join(s.root, "versions"))
+}
+
+// Scan attributes each shim to <name>@<version> for the active version and
+// reports the executables of every other installed version as inactive
+func (s *ShimEnv) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	versionsDir := filepath.Join(s.root, "versions")
+
+	// Versions are often symlinks to system installs, e.g. jenv's JDK homes
+	versions := readVersionDirs(versionsDir, true)
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	installs := map[string][]string{s.config.Tool: versions}
+	versionDir := func(tool, version string) string {
+		return filepath.Join(versionsDir, version)
+	}
+
+	shimTool := func(shim string, providers []string) string {
+		if len(providers) > 0 {
+			return providers[0]
+		}
+		return ""
+	}
+
+	selected := ""
+	active := func(tool string, installed []string) string {
+		selected = s.activeVersion(tool, installed)
+		return selected
+	}
+
+	binaries := versionManagerInstalls(s.Name(), installs, versionDir, filepath.Join(s.root, s.config.ShimsDir), shimTool, active)
+
+	for _, binary := range binaries {
+		switch {
+		case binary.Package == "":
+			// The manager's own executables, such as tfenv itself
+			binary.Package = s.Name()
+		case binary.Version != "":
+			binary.Package = fmt.Sprintf("%s@%s", s.Name(), binary.Version)
+		case selected == "":
+			binary.Package = s.Name() + "@system"
+		default:
+			// A shim the selected version doesn't provide
+			binary.Package = s.Name()
+		}
+	}
+
+	return binaries, nil
+}
+
+// activeVersion resolves the selected version from the version variable,
+// then the nearest local version file, then the global version file. pyenv
+// lets each name several versions, e.g. PYENV_VERSION=3.12.1:2.7.18, and
+// the first one that's installed is the active one.
+func (s *ShimEnv) activeVersion(tool string, installed []string) string {
+	requests := splitVersionList(os.Getenv(s.config.VersionEnv))
+
+	for dir := s.workDir; len(requests) == 0 && dir != ""; {
+		requests = readVersionFile(filepath.Join(dir, s.config.LocalFile))
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	if len(requests) == 0 {
+		requests = readVersionFile(filepath.Join(s.root, "version"))
+	}
+
+	for _, request := range requests {
+		if request == "system" {
+			return ""
+		}
+
+		// Version files may name the tool, e.g. "ruby-3.3.0" or "v20.11.1"
+		for _, candidate := range []string{request, strings.TrimPrefix(request, s.config.Tool+"-"), strings.TrimPrefix(request, "v")} {
+			if version := matchInstalledVersion(candidate, installed); version != "" {
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+// readVersionFile returns the versions named in a version file, in order.
+// pyenv allows several, separated by whitespace or colons.
+func readVersionFile(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var versions []string
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		line := lineScanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		versions = append(versions, splitVersionList(line)...)
+	}
+	return versions
+}
+
+// splitVersionList splits a list of versions separated by whitespace or
+// colons, e.g. "3.12.1:2.7.18"
+func splitVersionList(list string) []string {
+	return strings.Fields(strings.ReplaceAll(list, ":", " "))
+}
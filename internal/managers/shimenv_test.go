@@ -0,0 +1,174 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+func TestShimEnvScan(t *testing.T) {
+	root := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("RBENV_VERSION", "")
+
+	writeFile(t, filepath.Join(root, "versions", "3.2.2", "bin", "ruby"), "", 0755)
+	writeFile(t, filepath.Join(root, "versions", "3.3.0", "bin", "ruby"), "", 0755)
+	writeFile(t, filepath.Join(root, "versions", "3.3.0", "bin", "irb"), "", 0755)
+	writeFile(t, filepath.Join(root, "shims", "ruby"), "#!/usr/bin/env bash\n", 0755)
+	writeFile(t, filepath.Join(root, "shims", "irb"), "#!/usr/bin/env bash\n", 0755)
+	writeFile(t, filepath.Join(root, "version"), "3.2.2\n", 0644)
+	writeFile(t, filepath.Join(project, ".ruby-version"), "ruby-3.3.0\n", 0644)
+
+	config := DefaultShimEnvs[0]
+	rbenv := &ShimEnv{config: config, root: root, workDir: project}
+
+	binaries, err := rbenv.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]struct {
+		pkg      string
+		inactive bool
+	}{
+		filepath.Join(root, "shims", "ruby"):                    {"rbenv@3.3.0", false},
+		filepath.Join(root, "shims", "irb"):                     {"rbenv@3.3.0", false},
+		filepath.Join(root, "versions", "3.2.2", "bin", "ruby"): {"rbenv@3.2.2", true},
+	}
+
+	if len(binaries) != len(expected) {
+		t.Fatalf("Expected %d binaries, got %d", len(expected), len(binaries))
+	}
+
+	for _, binary := range binaries {
+		want, ok := expected[binary.Path]
+		if !ok {
+			t.Errorf("Unexpected binary: %s", binary)
+			continue
+		}
+		if binary.Package != want.pkg || binary.Inactive != want.inactive {
+			t.Errorf("%s: expected %s (inactive=%v), got %s (inactive=%v)", binary.Path, want.pkg, want.inactive, binary.Package, binary.Inactive)
+		}
+	}
+}
+
+func TestShimEnvScanWithoutShimsDir(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("TFENV_TERRAFORM_VERSION", "")
+
+	// tfenv keeps executables at the version root and a wrapper in bin
+	writeFile(t, filepath.Join(root, "versions", "1.5.7", "terraform"), "", 0755)
+	writeFile(t, filepath.Join(root, "bin", "terraform"), "#!/usr/bin/env bash\n", 0755)
+	writeFile(t, filepath.Join(root, "bin", "tfenv"), "#!/usr/bin/env bash\n", 0755)
+	writeFile(t, filepath.Join(root, "version"), "1.5.7\n", 0644)
+
+	var config ShimEnvConfig
+	for _, c := range DefaultShimEnvs {
+		if c.Name == "tfenv" {
+			config = c
+		}
+	}
+	tfenv := &ShimEnv{config: config, root: root, workDir: t.TempDir()}
+
+	binaries, err := tfenv.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packages := make(map[string]string)
+	for _, binary := range binaries {
+		packages[binary.Name] = binary.Package
+	}
+
+	if packages["terraform"] != "tfenv@1.5.7" || packages["tfenv"] != "tfenv" {
+		t.Errorf("Unexpected attribution: %v", packages)
+	}
+}
+
+func TestShimEnvVersionLists(t *testing.T) {
+	root := t.TempDir()
+	project := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "versions", "3.12.1", "bin", "python"), "", 0755)
+	writeFile(t, filepath.Join(root, "versions", "2.7.18", "bin", "python"), "", 0755)
+	writeFile(t, filepath.Join(root, "shims", "python"), "#!/usr/bin/env bash\n", 0755)
+
+	config := DefaultShimEnvs[1]
+	pyenv := &ShimEnv{config: config, root: root, workDir: project}
+
+	// The first installed version in each list is the active one
+	tests := []struct {
+		name    string
+		env     string
+		file    string
+		version string
+	}{
+		{"variable", "3.13.0:3.12.1:2.7.18", "", "3.12.1"},
+		{"variable with system first", "system:3.12.1", "", ""},
+		{"file with one per line", "", "3.13.0\n2.7.18\n3.12.1\n", "2.7.18"},
+		{"file with colons", "", "3.12.1:2.7.18\n", "3.12.1"},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("PYENV_VERSION", tt.env)
+		writeFile(t, filepath.Join(project, ".python-version"), tt.file, 0644)
+
+		if version := pyenv.activeVersion("python", []string{"2.7.18", "3.12.1"}); version != tt.version {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.version, version)
+		}
+	}
+
+	t.Setenv("PYENV_VERSION", "3.12.1:2.7.18")
+	binaries, err := pyenv.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shim *scanner.Binary
+	for _, binary := range binaries {
+		if binary.Path == filepath.Join(root, "shims", "python") {
+			shim = binary
+		}
+	}
+	if shim == nil || shim.Version != "3.12.1" {
+		t.Errorf("Expected the python shim to run 3.12.1, got %v", shim)
+	}
+}
+
+func TestShimEnvShimOfInactiveVersion(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("PYENV_VERSION", "3.12.1")
+
+	writeFile(t, filepath.Join(root, "versions", "3.12.1", "bin", "python"), "", 0755)
+	writeFile(t, filepath.Join(root, "versions", "2.7.18", "bin", "python"), "", 0755)
+	writeFile(t, filepath.Join(root, "versions", "2.7.18", "bin", "python2"), "", 0755)
+	writeFile(t, filepath.Join(root, "shims", "python"), "#!/usr/bin/env bash\n", 0755)
+	writeFile(t, filepath.Join(root, "shims", "python2"), "#!/usr/bin/env bash\n", 0755)
+
+	pyenv := &ShimEnv{config: DefaultShimEnvs[1], root: root, workDir: t.TempDir()}
+	binaries, err := pyenv.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shims := make(map[string]*scanner.Binary)
+	for _, binary := range binaries {
+		if binary.Kind == scanner.KindShim {
+			shims[binary.Name] = binary
+		}
+	}
+
+	if python := shims["python"]; python == nil || python.Version != "3.12.1" || python.Target != filepath.Join(root, "versions", "3.12.1", "bin", "python") {
+		t.Errorf("Expected python shim to run 3.12.1, got %+v", python)
+	}
+
+	// Only the inactive 2.7.18 provides python2
+	python2 := shims["python2"]
+	if python2 == nil || python2.Version != "" || python2.Target != "" || python2.Package != "pyenv" {
+		t.Fatalf("Expected python2 shim without a version or target, got %+v", python2)
+	}
+	if len(python2.Notes) != 1 || python2.Notes[0] != "not provided by the active python 3.12.1" {
+		t.Errorf("Unexpected python2 notes: %v", python2.Notes)
+	}
+}
@@ -0,0 +1,265 @@
+package managers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// shimHeaderSize is how much of an executable is read to classify it
+const shimHeaderSize = 4096
+
+// shimResolveTimeout bounds each command run to resolve a shim
+const shimResolveTimeout = 2 * time.Second
+
+// execLinePattern matches the command a wrapper script hands over to, as
+// in `exec /opt/tool/bin/tool "$@"` or `exec "$HOME/.tool/bin/tool" "$@"`,
+// where $HOME is expanded
+var execLinePattern = regexp.MustCompile(`(?m)^\s*exec\s+(?:-a\s+\S+\s+)?"?([^"\s]+)"?`)
+
+// nativeMagic lists the headers of ELF, Mach-O (thin and fat) and PE
+// executables
+var nativeMagic = [][]byte{
+	[]byte("\x7fELF"),
+	{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf},
+	{0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe},
+	{0xca, 0xfe, 0xba, 0xbe},
+	[]byte("MZ"),
+}
+
+// ShimFile is an executable being classified: where it is, the file it
+// resolves to through any symlinks, and the start of that file
+type ShimFile struct {
+	Name   string
+	Path   string
+	Target string
+	Header []byte
+}
+
+// ShimResolver recognizes the shims of one tool and finds the executable
+// each of them currently runs
+type ShimResolver interface {
+	Claims(shim ShimFile) bool
+	Resolve(ctx context.Context, executor system.CommandExecutor, shim ShimFile) string
+}
+
+// CommandShimResolver recognizes shims by their contents or what they link
+// to, and resolves them by asking their manager, e.g. `pyenv which python`.
+// Without a Which command the file a shim links to is its target.
+type CommandShimResolver struct {
+	Manager     string
+	Markers     []string // Strings the manager's shim scripts contain
+	LinkTargets []string // Names of the executable the manager's shims link to
+	PathMarkers []string // Path fragments of the files the manager's shims link to
+	Which       string   // Subcommand printing the executable a command runs
+}
+
+// DefaultShimResolvers recognizes the shims of common version managers
+var DefaultShimResolvers = defaultShimResolvers()
+
+// defaultShimResolvers builds DefaultShimResolvers, including one for each
+// rbenv-style manager, whose shims all run `<manager> exec`
+func defaultShimResolvers() []ShimResolver {
+	resolvers := []ShimResolver{
+		&CommandShimResolver{Manager: "asdf", Markers: []string{"asdf exec"}, Which: "which"},
+		&CommandShimResolver{Manager: "mise", LinkTargets: []string{"mise"}, Which: "which"},
+		&CommandShimResolver{Manager: "volta", LinkTargets: []string{"volta-shim"}, Which: "which"},
+		&CommandShimResolver{Manager: "corepack", PathMarkers: []string{"/corepack/shims/", "/corepack/dist/"}},
+		&CommandShimResolver{Manager: "brew", PathMarkers: []string{"/Homebrew/shims/"}},
+	}
+	for _, env := range DefaultShimEnvs {
+		resolvers = append(resolvers, &CommandShimResolver{
+			Manager: env.Name,
+			Markers: []string{"/libexec/" + env.Name + `" exec`, env.Name + " exec"},
+			Which:   "which",
+		})
+	}
+	return resolvers
+}
+
+// Claims reports whether shim belongs to the resolver's manager
+func (r *CommandShimResolver) Claims(shim ShimFile) bool {
+	for _, marker := range r.Markers {
+		if bytes.Contains(shim.Header, []byte(marker)) {
+			return true
+		}
+	}
+	for _, name := range r.LinkTargets {
+		if shim.Target != shim.Path && filepath.Base(shim.Target) == name {
+			return true
+		}
+	}
+	for _, marker := range r.PathMarkers {
+		if strings.Contains(filepath.ToSlash(shim.Target), marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the executable the shim runs, or "" when the manager
+// can't tell
+func (r *CommandShimResolver) Resolve(ctx context.Context, executor system.CommandExecutor, shim ShimFile) string {
+	if r.Which == "" {
+		if shim.Target != shim.Path {
+			return shim.Target
+		}
+		return ""
+	}
+	if executor == nil || !executor.IsAvailable(ctx, r.Manager) {
+		return ""
+	}
+
+	output, err := executor.Execute(ctx, r.Manager, r.Which, shim.Name)
+	if err != nil {
+		return ""
+	}
+	target, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	if !filepath.IsAbs(target) {
+		return ""
+	}
+	return target
+}
+
+// Shims classifies every binary as native, script, symlink, shim or
+// wrapper, and finds the executable each shim, wrapper and symlink runs
+type Shims struct {
+	executor  system.CommandExecutor
+	resolvers []ShimResolver
+	lookPath  func(name string) string
+	home      string
+}
+
+// shimLookup is a shim resolved by one resolver
+type shimLookup struct {
+	resolver ShimResolver
+	name     string
+	target   string
+}
+
+// NewShims creates a shim analyzer with the default resolvers
+func NewShims(executor system.CommandExecutor) *Shims {
+	return &Shims{
+		executor:  executor,
+		resolvers: DefaultShimResolvers,
+		lookPath:  system.LookPath,
+		home:      system.GetHomeDir(),
+	}
+}
+
+// Analyze sets the kind of every binary not classified by its manager, and
+// the target of the shims among them. Shims a version manager reported were
+// already looked up by it, so they aren't asked about again. Each shim is
+// resolved once per resolver, and a resolver that times out isn't asked
+// again.
+func (s *Shims) Analyze(result *scanner.ScanResult) {
+	resolved := make(map[shimLookup]string)
+	unresponsive := make(map[ShimResolver]bool)
+
+	for _, binary := range result.Binaries {
+		if binary.Kind != "" {
+			continue
+		}
+
+		shim, ok := readShimFile(binary.Name, binary.Path)
+		if !ok {
+			continue
+		}
+
+		resolver := s.resolver(shim)
+		binary.Kind, binary.Target = s.classify(shim, resolver != nil)
+		if binary.Kind != scanner.KindShim || unresponsive[resolver] {
+			continue
+		}
+
+		lookup := shimLookup{resolver: resolver, name: shim.Name, target: shim.Target}
+		target, ok := resolved[lookup]
+		if !ok {
+			ctx, cancel := context.WithTimeout(context.Background(), shimResolveTimeout)
+			target = resolver.Resolve(ctx, s.executor, shim)
+			if ctx.Err() != nil {
+				unresponsive[resolver] = true
+			}
+			cancel()
+			resolved[lookup] = target
+		}
+		binary.Target = target
+	}
+}
+
+// resolver returns the first resolver claiming shim, or nil
+func (s *Shims) resolver(shim ShimFile) ShimResolver {
+	for _, resolver := range s.resolvers {
+		if resolver.Claims(shim) {
+			return resolver
+		}
+	}
+	return nil
+}
+
+// classify returns the kind of an executable and, for symlinks and
+// wrappers, the executable it runs
+func (s *Shims) classify(shim ShimFile, claimed bool) (string, string) {
+	switch {
+	case claimed:
+		return scanner.KindShim, ""
+	case shim.Target != shim.Path:
+		return scanner.KindSymlink, shim.Target
+	}
+
+	for _, magic := range nativeMagic {
+		if bytes.HasPrefix(shim.Header, magic) {
+			return scanner.KindNative, ""
+		}
+	}
+	if !bytes.HasPrefix(shim.Header, []byte("#!")) {
+		return scanner.KindNative, ""
+	}
+
+	// A script that execs another program, other than an interpreter
+	// running the script's own code, wraps it
+	if match := execLinePattern.FindSubmatch(shim.Header); match != nil {
+		command := expandHome(string(match[1]), s.home)
+		switch {
+		case filepath.IsAbs(command):
+			return scanner.KindWrapper, command
+		case !strings.ContainsAny(command, "$/") && command != shim.Name:
+			if target := s.lookPath(command); target != "" {
+				return scanner.KindWrapper, target
+			}
+		}
+	}
+	return scanner.KindScript, ""
+}
+
+// readShimFile resolves an executable's symlinks and reads the start of the
+// file it resolves to
+func readShimFile(name, path string) (ShimFile, bool) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return ShimFile{}, false
+	}
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		target = path
+	}
+
+	file, err := os.Open(target)
+	if err != nil {
+		return ShimFile{}, false
+	}
+	defer file.Close()
+
+	header, err := io.ReadAll(io.LimitReader(file, shimHeaderSize))
+	if err != nil {
+		return ShimFile{}, false
+	}
+	return ShimFile{Name: name, Path: path, Target: target, Header: header}, true
+}
@@ -0,0 +1,114 @@
+package managers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+)
+
+func TestShimsAnalyze(t *testing.T) {
+	root := t.TempDir()
+	bin := filepath.Join(root, "bin")
+
+	writeFile(t, filepath.Join(bin, "native"), "\x7fELF\x02\x01", 0755)
+	writeFile(t, filepath.Join(bin, "script"), "#!/bin/sh\necho hello\n", 0755)
+	writeFile(t, filepath.Join(root, "opt", "tool", "bin", "tool"), "\x7fELF", 0755)
+	writeFile(t, filepath.Join(bin, "tool"), "#!/bin/sh\nexport TOOL_HOME=/opt/tool\nexec "+filepath.Join(root, "opt", "tool", "bin", "tool")+" \"$@\"\n", 0755)
+	writeFile(t, filepath.Join(bin, "fmt"), "#!/bin/sh\nexec deno fmt \"$@\"\n", 0755)
+	writeFile(t, filepath.Join(root, "home", ".tool", "bin", "tool"), "\x7fELF", 0755)
+	writeFile(t, filepath.Join(bin, "home-tool"), "#!/bin/sh\nexec \"$HOME/.tool/bin/tool\" \"$@\"\n", 0755)
+	writeFile(t, filepath.Join(bin, "env-tool"), "#!/bin/sh\nexec \"$TOOL_HOME/bin/tool\" \"$@\"\n", 0755)
+	symlink(t, filepath.Join(root, "opt", "tool", "bin", "tool"), filepath.Join(bin, "tool-link"))
+
+	// A pyenv shim resolved by `pyenv which`, and a Corepack shim by its link
+	pyenvShim := filepath.Join(root, ".pyenv", "shims", "python")
+	writeFile(t, pyenvShim, "#!/usr/bin/env bash\nset -e\nprogram=\"${0##*/}\"\nexport PYENV_ROOT=\"/home/me/.pyenv\"\nexec \"/usr/lib/pyenv/libexec/pyenv\" exec \"$program\" \"$@\"\n", 0755)
+	yarnScript := filepath.Join(root, "node", "lib", "node_modules", "corepack", "dist", "yarn.js")
+	writeFile(t, yarnScript, "#!/usr/bin/env node\n", 0755)
+	symlink(t, yarnScript, filepath.Join(root, "node", "bin", "yarn"))
+
+	paths := map[string]string{
+		"native":    filepath.Join(bin, "native"),
+		"script":    filepath.Join(bin, "script"),
+		"tool":      filepath.Join(bin, "tool"),
+		"fmt":       filepath.Join(bin, "fmt"),
+		"home-tool": filepath.Join(bin, "home-tool"),
+		"env-tool":  filepath.Join(bin, "env-tool"),
+		"tool-link": filepath.Join(bin, "tool-link"),
+		"python":    pyenvShim,
+		"yarn":      filepath.Join(root, "node", "bin", "yarn"),
+	}
+	result := scanner.NewScanResult()
+	for name, path := range paths {
+		result.AddBinary(&scanner.Binary{Name: name, Path: path, Manager: "manual"})
+	}
+	// The same shim reported twice is resolved once
+	result.AddBinary(&scanner.Binary{Name: "python", Path: pyenvShim, Manager: "bundle"})
+	// Shims a version manager reported were already looked up by it,
+	// whether it found their target or not
+	resolved := &scanner.Binary{Name: "ruby", Path: "/home/me/.rbenv/shims/ruby", Manager: "rbenv", Kind: scanner.KindShim, Target: "/home/me/.rbenv/versions/3.3.0/bin/ruby"}
+	result.AddBinary(resolved)
+	pyenvShim2 := filepath.Join(root, ".pyenv", "shims", "python2")
+	writeFile(t, pyenvShim2, "#!/usr/bin/env bash\nexec \"/usr/lib/pyenv/libexec/pyenv\" exec \"$program\" \"$@\"\n", 0755)
+	result.AddBinary(&scanner.Binary{Name: "python2", Path: pyenvShim2, Manager: "pyenv", Kind: scanner.KindShim})
+
+	executor := &fakeExecutor{
+		available: map[string]bool{"pyenv": true},
+		outputs:   map[string]string{"pyenv which python": "/home/me/.pyenv/versions/3.12.1/bin/python\n"},
+	}
+	shims := &Shims{
+		executor:  executor,
+		resolvers: DefaultShimResolvers,
+		home:      filepath.Join(root, "home"),
+		lookPath: func(name string) string {
+			if name == "deno" {
+				return "/home/me/.deno/bin/deno"
+			}
+			return ""
+		},
+	}
+	shims.Analyze(result)
+
+	expected := map[string]string{
+		"native":    "native ",
+		"script":    "script ",
+		"tool":      "wrapper " + filepath.Join(root, "opt", "tool", "bin", "tool"),
+		"fmt":       "wrapper /home/me/.deno/bin/deno",
+		"home-tool": "wrapper " + filepath.Join(root, "home", ".tool", "bin", "tool"),
+		"env-tool":  "script ",
+		"tool-link": "symlink " + filepath.Join(root, "opt", "tool", "bin", "tool"),
+		"python":    "shim /home/me/.pyenv/versions/3.12.1/bin/python",
+		"yarn":      "shim " + yarnScript,
+		"ruby":      "shim /home/me/.rbenv/versions/3.3.0/bin/ruby",
+		"python2":   "shim ",
+	}
+	for _, binary := range result.Binaries {
+		if got := binary.Kind + " " + binary.Target; got != expected[binary.Name] {
+			t.Errorf("Expected %s to be %q, got %q", binary.Name, expected[binary.Name], got)
+		}
+	}
+	if len(executor.calls) != 1 {
+		t.Errorf("Expected only the pyenv shim to be resolved by command, got %v", executor.calls)
+	}
+}
+
+func TestVersionManagerShimTargets(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "installs", "nodejs", "20.11.0", "bin", "node"), "", 0755)
+	writeFile(t, filepath.Join(root, "shims", "node"), "#!/usr/bin/env bash\nexec asdf exec \"node\" \"$@\"\n", 0755)
+
+	binaries := versionManagerInstalls(
+		"asdf",
+		map[string][]string{"nodejs": {"20.11.0"}},
+		func(tool, version string) string { return filepath.Join(root, "installs", tool, version) },
+		filepath.Join(root, "shims"),
+		func(shim string, providers []string) string { return "nodejs" },
+		func(tool string, installed []string) string { return "20.11.0" },
+	)
+
+	node := findBinary(binaries, "node")
+	if node == nil || node.Kind != scanner.KindShim || node.Target != filepath.Join(root, "installs", "nodejs", "20.11.0", "bin", "node") {
+		t.Errorf("Expected the node shim to target the active install, got %+v", node)
+	}
+}
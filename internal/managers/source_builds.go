@@ -0,0 +1,262 @@
+package managers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// defaultStowDirs lists the stow directories GNU Stow and xstow users
+// conventionally keep packages in
+var defaultStowDirs = []string{
+	"/usr/local/stow",
+	"/usr/local/xstow",
+	"/opt/stow",
+}
+
+// buildManifestsFile names the file listing registered build manifests
+const buildManifestsFile = "build-manifests"
+
+// SourceBuilds implements the PackageManager interface for software built
+// from source: packages in GNU Stow or xstow directories, and make install
+// runs recorded by a registered CMake install_manifest.txt
+type SourceBuilds struct {
+	executor      system.CommandExecutor
+	stowDirs      []string
+	manifestsFile string
+}
+
+// buildManifest is a registered install manifest and the project it installed
+type buildManifest struct {
+	Project string
+	Path    string
+}
+
+// NewSourceBuilds creates a new source builds package manager
+func NewSourceBuilds(executor system.CommandExecutor) *SourceBuilds {
+	stowDirs := defaultStowDirs
+	if dir := os.Getenv("STOW_DIR"); dir != "" {
+		stowDirs = append([]string{dir}, stowDirs...)
+	}
+
+	return &SourceBuilds{
+		executor:      executor,
+		stowDirs:      stowDirs,
+		manifestsFile: filepath.Join(snappointConfigDir(), buildManifestsFile),
+	}
+}
+
+// Name returns the name of the package manager
+func (s *SourceBuilds) Name() string {
+	return "source"
+}
+
+// IsAvailable checks if a stow directory or registered manifest exists
+func (s *SourceBuilds) IsAvailable(ctx context.Context) bool {
+	for _, dir := range s.stowDirs {
+		if isDir(dir) {
+			return true
+		}
+	}
+	return len(s.readManifests()) > 0
+}
+
+// Scan reports the executables each stow package links into its target
+// directory and each registered manifest installed into a bin directory,
+// all attributed to their project with how to uninstall them as a group
+func (s *SourceBuilds) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	var binaries []*scanner.Binary
+	for _, dir := range s.stowDirs {
+		binaries = append(binaries, s.scanStowDir(dir)...)
+	}
+	for _, manifest := range s.readManifests() {
+		binaries = append(binaries, s.scanManifest(manifest)...)
+	}
+	return binaries, nil
+}
+
+// scanStowDir finds the executables of each package in a stow directory
+// that are linked into the target, the stow directory's parent. Stow may
+// link a whole bin directory when only one package uses it, so links are
+// compared by their resolved paths.
+func (s *SourceBuilds) scanStowDir(stowDir string) []*scanner.Binary {
+	packages, err := os.ReadDir(stowDir)
+	if err != nil {
+		return nil
+	}
+
+	target := filepath.Dir(stowDir)
+	validator := system.NewFileValidator()
+
+	var binaries []*scanner.Binary
+	for _, pkg := range packages {
+		if !pkg.IsDir() || strings.HasPrefix(pkg.Name(), ".") {
+			continue
+		}
+
+		pkgDir := filepath.Join(stowDir, pkg.Name())
+		project, version := splitProjectVersion(pkg.Name())
+
+		for _, binDir := range []string{"bin", "sbin"} {
+			for _, name := range validator.ListExecutables(filepath.Join(pkgDir, binDir)) {
+				linked := filepath.Join(target, binDir, name)
+				if !sameFile(linked, filepath.Join(pkgDir, binDir, name)) {
+					continue
+				}
+
+				binaries = append(binaries, &scanner.Binary{
+					Name:    name,
+					Path:    linked,
+					Manager: s.Name(),
+					Version: version,
+					Package: project,
+					Source:  pkgDir,
+					Notes:   []string{fmt.Sprintf("built from source: %s (uninstall with stow -D -d %s -t %s %s)", pkg.Name(), stowDir, target, pkg.Name())},
+				})
+			}
+		}
+	}
+
+	return binaries
+}
+
+// scanManifest reports the executables a build manifest installed into a
+// bin or sbin directory that are still present
+func (s *SourceBuilds) scanManifest(manifest buildManifest) []*scanner.Binary {
+	files := readLines(manifest.Path)
+	validator := system.NewFileValidator()
+
+	var binaries []*scanner.Binary
+	for _, path := range files {
+		if base := filepath.Base(filepath.Dir(path)); base != "bin" && base != "sbin" {
+			continue
+		}
+		if !validator.IsBinaryExecutable(path) {
+			continue
+		}
+
+		binaries = append(binaries, &scanner.Binary{
+			Name:    filepath.Base(path),
+			Path:    path,
+			Manager: s.Name(),
+			Package: manifest.Project,
+			Source:  manifest.Path,
+			Notes:   []string{fmt.Sprintf("built from source: %s (uninstall all %d installed files with xargs rm < %s)", manifest.Project, len(files), manifest.Path)},
+		})
+	}
+
+	return binaries
+}
+
+// readManifests reads the registered manifests, one per line as
+// "<path>" or "<project> = <path>". Without a project name it's taken from
+// the source tree: the manifest's directory, or its parent for build dirs.
+func (s *SourceBuilds) readManifests() []buildManifest {
+	var manifests []buildManifest
+	for _, line := range readLines(s.manifestsFile) {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		project, path, ok := strings.Cut(line, " = ")
+		if !ok {
+			path, project = line, manifestProject(line)
+		}
+		manifests = append(manifests, buildManifest{Project: strings.TrimSpace(project), Path: system.ExpandPath(strings.TrimSpace(path))})
+	}
+	return manifests
+}
+
+// RegisterBuildManifest adds a build manifest, such as a CMake
+// install_manifest.txt, to the list the source builds manager reads.
+// project may be empty to name it after its source tree.
+func RegisterBuildManifest(path, project string) (string, error) {
+	path, err := filepath.Abs(system.ExpandPath(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	if project == "" {
+		project = manifestProject(path)
+	}
+
+	file := filepath.Join(snappointConfigDir(), buildManifestsFile)
+	for _, line := range readLines(file) {
+		if existing, registered, ok := strings.Cut(line, " = "); (ok && registered == path) || existing == path {
+			return "", fmt.Errorf("%s is already registered", path)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "%s = %s\n", project, path); err != nil {
+		return "", err
+	}
+	return project, nil
+}
+
+// manifestProject names a project after the directory its manifest is in,
+// skipping build directories such as build/ or cmake-build-release/
+func manifestProject(path string) string {
+	dir := filepath.Dir(path)
+	if base := strings.ToLower(filepath.Base(dir)); strings.Contains(base, "build") || base == "out" {
+		dir = filepath.Dir(dir)
+	}
+	return filepath.Base(dir)
+}
+
+// splitProjectVersion splits a stow package name such as jq-1.7.1 into
+// its project and version, when it ends in one
+func splitProjectVersion(name string) (string, string) {
+	idx := strings.LastIndex(name, "-")
+	if idx <= 0 || idx == len(name)-1 || name[idx+1] < '0' || name[idx+1] > '9' {
+		return name, ""
+	}
+	return name[:idx], name[idx+1:]
+}
+
+// sameFile reports whether two paths resolve to the same file
+func sameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(infoA, infoB)
+}
+
+// readLines returns the non-empty lines of a file, trimmed
+func readLines(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var lines []string
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		if line := strings.TrimSpace(lineScanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,76 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSourceBuildsScan(t *testing.T) {
+	root := t.TempDir()
+	stowDir := filepath.Join(root, "stow")
+
+	// jq is the only package with a bin dir, so stow folded it into one link
+	writeFile(t, filepath.Join(stowDir, "jq-1.7.1", "bin", "jq"), "", 0755)
+	symlink(t, "stow/jq-1.7.1/bin", filepath.Join(root, "bin"))
+	writeFile(t, filepath.Join(stowDir, "tmux", "sbin", "tmux"), "", 0755)
+	symlink(t, "../stow/tmux/sbin/tmux", filepath.Join(root, "sbin", "tmux"))
+	// An unstowed package links nothing
+	writeFile(t, filepath.Join(stowDir, "fd-9.0.0", "sbin", "fd"), "", 0755)
+
+	// A CMake build registered without a project name
+	manifest := filepath.Join(root, "src", "neovim", "build", "install_manifest.txt")
+	writeFile(t, filepath.Join(root, "local", "bin", "nvim"), "", 0755)
+	writeFile(t, filepath.Join(root, "local", "share", "nvim", "runtime", "filetype.lua"), "", 0644)
+	writeFile(t, manifest, filepath.Join(root, "local", "bin", "nvim")+"\n"+filepath.Join(root, "local", "share", "nvim", "runtime", "filetype.lua")+"\n"+filepath.Join(root, "local", "bin", "removed")+"\n", 0644)
+	manifestsFile := filepath.Join(root, "config", buildManifestsFile)
+	writeFile(t, manifestsFile, "# registered by snappoint manifest add\n"+manifest+"\n", 0644)
+
+	binaries, err := (&SourceBuilds{stowDirs: []string{stowDir}, manifestsFile: manifestsFile}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binaries) != 3 {
+		t.Fatalf("Expected 3 binaries, got %d", len(binaries))
+	}
+
+	jq := findBinary(binaries, "jq")
+	if jq == nil || jq.Path != filepath.Join(root, "bin", "jq") || jq.Package != "jq" || jq.Version != "1.7.1" {
+		t.Errorf("Expected jq 1.7.1 from its folded bin link, got %+v", jq)
+	} else if !strings.Contains(jq.Notes[0], "stow -D -d "+stowDir+" -t "+root+" jq-1.7.1") {
+		t.Errorf("Expected a stow uninstall note, got %q", jq.Notes[0])
+	}
+
+	if tmux := findBinary(binaries, "tmux"); tmux == nil || tmux.Package != "tmux" || tmux.Version != "" {
+		t.Errorf("Expected unversioned tmux, got %+v", tmux)
+	}
+
+	nvim := findBinary(binaries, "nvim")
+	if nvim == nil || nvim.Package != "neovim" || nvim.Source != manifest {
+		t.Errorf("Expected nvim from the neovim manifest, got %+v", nvim)
+	} else if !strings.Contains(nvim.Notes[0], "all 3 installed files") {
+		t.Errorf("Expected a group uninstall note, got %q", nvim.Notes[0])
+	}
+}
+
+func TestRegisterBuildManifest(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("SNAPPOINT_CONFIG_DIR", filepath.Join(root, "config"))
+
+	manifest := filepath.Join(root, "src", "fish-shell", "install_manifest.txt")
+	writeFile(t, manifest, "", 0644)
+
+	project, err := RegisterBuildManifest(manifest, "")
+	if err != nil || project != "fish-shell" {
+		t.Fatalf("Expected project fish-shell, got %q (%v)", project, err)
+	}
+	if _, err := RegisterBuildManifest(manifest, "fish"); err == nil {
+		t.Error("Expected registering a manifest twice to fail")
+	}
+
+	manifests := NewSourceBuilds(nil).readManifests()
+	if len(manifests) != 1 || manifests[0] != (buildManifest{Project: "fish-shell", Path: manifest}) {
+		t.Errorf("Expected the registered manifest, got %+v", manifests)
+	}
+}
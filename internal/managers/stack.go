@@ -0,0 +1,140 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// Stack implements the PackageManager interface for executables copied to
+// the local bin directory by `stack install`
+type Stack struct {
+	executor    system.CommandExecutor
+	stackRoot   string
+	localBinDir string
+}
+
+// stackBuild is an executable in one of stack's install roots
+type stackBuild struct {
+	Package    string
+	Version    string
+	GHCVersion string
+	Path       string
+}
+
+// NewStack creates a new stack package manager
+func NewStack(executor system.CommandExecutor) *Stack {
+	stackRoot := os.Getenv("STACK_ROOT")
+	if stackRoot == "" {
+		stackRoot = filepath.Join(system.GetHomeDir(), ".stack")
+	}
+
+	return &Stack{
+		executor:    executor,
+		stackRoot:   stackRoot,
+		localBinDir: filepath.Join(system.GetHomeDir(), ".local", "bin"),
+	}
+}
+
+// Name returns the name of the package manager
+func (s *Stack) Name() string {
+	return "stack"
+}
+
+// IsAvailable checks if the stack root exists
+func (s *Stack) IsAvailable(ctx context.Context) bool {
+	return isDir(s.stackRoot)
+}
+
+// Scan matches executables in the local bin directory to the builds stack
+// copied them from. stack keeps no record of the copy, so an executable is
+// attributed when a build of the same name and size exists in a snapshot or
+// the global project's install root.
+func (s *Stack) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	builds := s.readBuilds()
+
+	var binaries []*scanner.Binary
+	for _, name := range system.NewFileValidator().ListExecutables(s.localBinDir) {
+		path := filepath.Join(s.localBinDir, name)
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		var match *stackBuild
+		for _, build := range builds[name] {
+			buildInfo, err := os.Stat(build.Path)
+			if err == nil && buildInfo.Size() == info.Size() && (match == nil || compareVersions(build.Version, match.Version) > 0) {
+				match = build
+			}
+		}
+		if match == nil {
+			continue
+		}
+
+		binaries = append(binaries, &scanner.Binary{
+			Name:    name,
+			Path:    path,
+			Manager: s.Name(),
+			Version: match.Version,
+			Package: match.Package,
+			Notes:   []string{"built with GHC " + match.GHCVersion},
+		})
+	}
+
+	return binaries, nil
+}
+
+// readBuilds indexes the executables of every install root by name. Roots
+// end in <ghc-version>, hold executables in bin/, and record the packages
+// built for their executables in installed-packages/<package>-<version>.
+func (s *Stack) readBuilds() map[string][]*stackBuild {
+	roots, _ := filepath.Glob(filepath.Join(s.stackRoot, "snapshots", "*", "*", "*"))
+	projectRoots, _ := filepath.Glob(filepath.Join(s.stackRoot, "global-project", ".stack-work", "install", "*", "*", "*"))
+
+	builds := make(map[string][]*stackBuild)
+	for _, root := range append(roots, projectRoots...) {
+		entries, _ := os.ReadDir(filepath.Join(root, "installed-packages"))
+		packages := make(map[string]string)
+		for _, entry := range entries {
+			if name, version, ok := cutPackageVersion(entry.Name()); ok {
+				packages[name] = version
+			}
+		}
+
+		for _, exe := range system.NewFileValidator().ListExecutables(filepath.Join(root, "bin")) {
+			build := &stackBuild{
+				Package:    exe,
+				GHCVersion: filepath.Base(root),
+				Path:       filepath.Join(root, "bin", exe),
+			}
+
+			// Executables are usually named after their package; otherwise
+			// a root with a single executable package must have built it
+			if version, ok := packages[exe]; ok {
+				build.Version = version
+			} else if len(packages) == 1 {
+				for name, version := range packages {
+					build.Package, build.Version = name, version
+				}
+			}
+
+			builds[exe] = append(builds[exe], build)
+		}
+	}
+
+	return builds
+}
+
+// cutPackageVersion splits a Haskell package id such as hlint-3.6.1
+func cutPackageVersion(id string) (string, string, bool) {
+	idx := strings.LastIndex(id, "-")
+	if idx <= 0 || idx == len(id)-1 || strings.Trim(id[idx+1:], "0123456789.") != "" {
+		return "", "", false
+	}
+	return id[:idx], id[idx+1:], true
+}
@@ -0,0 +1,80 @@
+package managers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// writeStackBuild creates an install root with an executable and the
+// packages recorded as installed into it
+func writeStackBuild(t *testing.T, root, exe, content string, packages ...string) {
+	t.Helper()
+	writeFile(t, filepath.Join(root, "bin", exe), content, 0755)
+	for _, pkg := range packages {
+		writeFile(t, filepath.Join(root, "installed-packages", pkg), "", 0644)
+	}
+}
+
+func TestStackScan(t *testing.T) {
+	stackRoot := t.TempDir()
+	localBin := t.TempDir()
+
+	snapshots := filepath.Join(stackRoot, "snapshots", "x86_64-linux")
+	writeStackBuild(t, filepath.Join(snapshots, "a1b2", "9.2.8"), "hlint", "hlint", "hlint-3.5")
+	writeStackBuild(t, filepath.Join(snapshots, "c3d4", "9.4.8"), "hlint", "hlint", "hlint-3.6.1", "extra-1.7.14")
+	// pandoc is built from the pandoc-cli package, the only one in its root
+	writeStackBuild(t, filepath.Join(stackRoot, "global-project", ".stack-work", "install", "x86_64-linux", "e5f6", "9.4.8"), "pandoc", "pandoc", "pandoc-cli-3.1.11")
+	writeStackBuild(t, filepath.Join(snapshots, "c3d4", "9.4.8"), "shellcheck", "shellcheck", "ShellCheck-0.9.0")
+
+	writeFile(t, filepath.Join(localBin, "hlint"), "hlint", 0755)
+	writeFile(t, filepath.Join(localBin, "pandoc"), "pandoc", 0755)
+	// A different build of the same name is someone else's
+	writeFile(t, filepath.Join(localBin, "shellcheck"), "downloaded shellcheck", 0755)
+	symlink(t, filepath.Join(localBin, "hlint"), filepath.Join(localBin, "hlint-link"))
+
+	binaries, err := (&Stack{stackRoot: stackRoot, localBinDir: localBin}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"hlint":  "hlint 3.6.1 built with GHC 9.4.8",
+		"pandoc": "pandoc-cli 3.1.11 built with GHC 9.4.8",
+	}
+	if len(binaries) != len(expected) {
+		t.Errorf("Expected %d binaries, got %v", len(expected), binaries)
+	}
+	for _, binary := range binaries {
+		got := binary.Package + " " + binary.Version
+		if len(binary.Notes) > 0 {
+			got += " " + binary.Notes[0]
+		}
+		if got != expected[binary.Name] {
+			t.Errorf("Expected %s to be %q, got %q", binary.Name, expected[binary.Name], got)
+		}
+	}
+}
+
+func TestCutPackageVersion(t *testing.T) {
+	tests := []struct {
+		id      string
+		name    string
+		version string
+		ok      bool
+	}{
+		{"hlint-3.6.1", "hlint", "3.6.1", true},
+		{"pandoc-cli-3.1.11", "pandoc-cli", "3.1.11", true},
+		{"ShellCheck-0.9.0", "ShellCheck", "0.9.0", true},
+		{"hlint", "", "", false},
+		{"hlint-", "", "", false},
+		{"pandoc-cli", "", "", false},
+	}
+
+	for _, tt := range tests {
+		name, version, ok := cutPackageVersion(tt.id)
+		if name != tt.name || version != tt.version || ok != tt.ok {
+			t.Errorf("cutPackageVersion(%q): expected %q %q %v, got %q %q %v", tt.id, tt.name, tt.version, tt.ok, name, version, ok)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package managers
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// tomlTables maps a table name ("" for the root table) to its keys and raw values
+type tomlTables map[string]map[string]string
+
+// readTOML reads the subset of TOML that tool receipts and config files use:
+// tables, key/value pairs, strings, inline tables and arrays, which may span
+// several lines. Values are returned raw for the tomlString, tomlArray and
+// tomlInlineTable helpers to decode.
+func readTOML(path string) (tomlTables, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tables := tomlTables{"": {}}
+	table := ""
+
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		line := strings.TrimSpace(stripTOMLComment(lineScanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && !strings.Contains(line, "=") {
+			table = strings.TrimSpace(strings.Trim(line, "[]"))
+			if _, exists := tables[table]; !exists {
+				tables[table] = make(map[string]string)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		// Keep reading until multi-line arrays and inline tables are closed
+		for tomlDepth(value) > 0 && lineScanner.Scan() {
+			value += " " + strings.TrimSpace(stripTOMLComment(lineScanner.Text()))
+		}
+
+		tables[table][unquoteTOML(strings.TrimSpace(key))] = value
+	}
+
+	return tables, lineScanner.Err()
+}
+
+// tomlString decodes a string or bare scalar value
+func tomlString(raw string) string {
+	return unquoteTOML(strings.TrimSpace(raw))
+}
+
+// tomlArray splits an array value into its raw items
+func tomlArray(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+		return nil
+	}
+	return splitTOMLList(raw[1 : len(raw)-1])
+}
+
+// tomlInlineTable decodes an inline table such as { name = "ruff" } into
+// its keys and raw values
+func tomlInlineTable(raw string) map[string]string {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "{") || !strings.HasSuffix(raw, "}") {
+		return nil
+	}
+
+	table := make(map[string]string)
+	for _, pair := range splitTOMLList(raw[1 : len(raw)-1]) {
+		if key, value, ok := strings.Cut(pair, "="); ok {
+			table[unquoteTOML(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	return table
+}
+
+// splitTOMLList splits on commas that aren't inside strings, arrays or
+// inline tables, dropping empty items left by trailing commas
+func splitTOMLList(s string) []string {
+	var items []string
+	depth := 0
+	var quote byte
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			if item := strings.TrimSpace(s[start:i]); item != "" {
+				items = append(items, item)
+			}
+			start = i + 1
+		}
+	}
+
+	if item := strings.TrimSpace(s[start:]); item != "" {
+		items = append(items, item)
+	}
+	return items
+}
+
+// tomlDepth returns how many arrays or inline tables are left open in value
+func tomlDepth(value string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// stripTOMLComment removes a trailing comment that isn't inside a string
+func stripTOMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquoteTOML strips surrounding quotes and trailing comments from a simple
+// TOML scalar
+func unquoteTOML(value string) string {
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if idx := strings.Index(value, "#"); idx >= 0 {
+		value = value[:idx]
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,305 @@
+package managers
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// toolVersionsFile is the per-directory version file shared by asdf and mise
+const toolVersionsFile = ".tool-versions"
+
+// toolRequests maps a tool name to its requested versions in order of preference
+type toolRequests map[string][]string
+
+// merge adds requests for tools that aren't already present, so the
+// receiver keeps precedence over other
+func (r toolRequests) merge(other toolRequests) {
+	for tool, versions := range other {
+		if _, exists := r[tool]; !exists {
+			r[tool] = versions
+		}
+	}
+}
+
+// activeVersion returns a resolver for the selected version of each tool.
+// An environment override such as ASDF_NODEJS_VERSION wins over version
+// files, and "system" means no managed version is selected.
+func (r toolRequests) activeVersion(envPrefix string) func(tool string, installed []string) string {
+	return func(tool string, installed []string) string {
+		if override := envVersionOverride(envPrefix, tool); override != "" {
+			return matchInstalledVersion(override, installed)
+		}
+		for _, request := range r[tool] {
+			if request == "system" {
+				return ""
+			}
+			if version := matchInstalledVersion(request, installed); version != "" {
+				return version
+			}
+		}
+		return ""
+	}
+}
+
+// parseToolVersions reads a .tool-versions file. Each line names a tool
+// followed by one or more versions, the first being preferred.
+func parseToolVersions(path string) toolRequests {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	requests := make(toolRequests)
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		line := lineScanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		requests[fields[0]] = fields[1:]
+	}
+
+	return requests
+}
+
+// parseMiseTOML reads the [tools] table of a mise config file. Values may be
+// a version string, an array of versions or an inline table with a
+// version key, e.g. python = { version = "3.11" }.
+func parseMiseTOML(path string) toolRequests {
+	tables, err := readTOML(path)
+	if err != nil {
+		return nil
+	}
+
+	requests := make(toolRequests)
+	for key, value := range tables["tools"] {
+		if versions := parseMiseToolValue(value); len(versions) > 0 {
+			requests[normalizeMiseTool(key)] = versions
+		}
+	}
+
+	return requests
+}
+
+// parseMiseToolValue extracts versions from the right-hand side of a
+// [tools] entry
+func parseMiseToolValue(value string) []string {
+	if items := tomlArray(value); items != nil {
+		var versions []string
+		for _, item := range items {
+			if v := tomlString(item); v != "" {
+				versions = append(versions, v)
+			}
+		}
+		return versions
+	}
+
+	if table := tomlInlineTable(value); table != nil {
+		if v := tomlString(table["version"]); v != "" {
+			return []string{v}
+		}
+		return nil
+	}
+
+	if v := tomlString(value); v != "" {
+		return []string{v}
+	}
+	return nil
+}
+
+// normalizeMiseTool converts a backend-qualified tool name such as
+// "aqua:hashicorp/terraform" into the directory name mise installs it under
+func normalizeMiseTool(tool string) string {
+	return strings.NewReplacer(":", "-", "/", "-").Replace(tool)
+}
+
+// findToolRequests walks from dir up to the filesystem root, reading
+// version files with read. Files in directories closer to dir take
+// precedence over those further up.
+func findToolRequests(dir string, read func(dir string) toolRequests) toolRequests {
+	requests := make(toolRequests)
+	if dir == "" {
+		return requests
+	}
+
+	for {
+		requests.merge(read(dir))
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return requests
+}
+
+// envVersionOverride looks up a tool's version override in the environment
+// using the <PREFIX>_<TOOL>_VERSION convention shared by asdf and mise
+func envVersionOverride(prefix, tool string) string {
+	name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(tool))
+	return os.Getenv(prefix + "_" + name + "_VERSION")
+}
+
+// readInstalls lists every tool and version under an installs directory laid
+// out as <tool>/<version>. Symlinked version directories are aliases
+// (e.g. mise's "latest" or "20") and are skipped.
+func readInstalls(installsDir string) map[string][]string {
+	installs := make(map[string][]string)
+
+	tools, err := os.ReadDir(installsDir)
+	if err != nil {
+		return installs
+	}
+
+	for _, tool := range tools {
+		if !tool.IsDir() {
+			continue
+		}
+
+		if versions := readVersionDirs(filepath.Join(installsDir, tool.Name()), false); len(versions) > 0 {
+			installs[tool.Name()] = versions
+		}
+	}
+
+	return installs
+}
+
+// readVersionDirs lists the version directories in dir, sorted by version.
+// Symlinked versions are included only when followLinks is set.
+func readVersionDirs(dir string, followLinks bool) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if entry.Type()&os.ModeSymlink != 0 {
+			if !followLinks || !isDir(filepath.Join(dir, entry.Name())) {
+				continue
+			}
+		} else if !entry.IsDir() {
+			continue
+		}
+		versions = append(versions, entry.Name())
+	}
+
+	sortVersions(versions)
+	return versions
+}
+
+// installExecutables lists the executables an installed version provides.
+// Most plugins use a bin directory; some place executables at the root.
+func installExecutables(versionDir string) (string, []string) {
+	validator := system.NewFileValidator()
+
+	binDir := filepath.Join(versionDir, "bin")
+	if names := validator.ListExecutables(binDir); len(names) > 0 {
+		return binDir, names
+	}
+
+	return versionDir, validator.ListExecutables(versionDir)
+}
+
+// versionManagerInstalls builds binaries for a tool version manager. The
+// shims on PATH are attributed to the active version of the tool that
+// provides them, or to no version when the active one doesn't, while
+// executables of every other installed version are reported as inactive.
+// installs maps each tool to its installed versions and versionDir locates
+// one of them. shimTool maps a shim name to its tool, and active returns
+// the selected version for a tool (or "" when none is selected).
+func versionManagerInstalls(
+	managerName string,
+	installs map[string][]string,
+	versionDir func(tool, version string) string,
+	shimsDir string,
+	shimTool func(shim string, providers []string) string,
+	active func(tool string, installed []string) string,
+) []*scanner.Binary {
+	var binaries []*scanner.Binary
+
+	// Index which tools provide each executable name, in tool name order so
+	// a shim several tools provide is attributed the same way every scan
+	tools := make([]string, 0, len(installs))
+	for tool := range installs {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	providers := make(map[string][]string)
+	activeVersions := make(map[string]string)
+	for _, tool := range tools {
+		versions := installs[tool]
+		activeVersion := active(tool, versions)
+		activeVersions[tool] = activeVersion
+
+		seen := make(map[string]bool)
+		for _, version := range versions {
+			binDir, names := installExecutables(versionDir(tool, version))
+			for _, name := range names {
+				if !seen[name] {
+					seen[name] = true
+					providers[name] = append(providers[name], tool)
+				}
+
+				if version == activeVersion {
+					continue
+				}
+				binaries = append(binaries, &scanner.Binary{
+					Name:     name,
+					Path:     filepath.Join(binDir, name),
+					Manager:  managerName,
+					Version:  version,
+					Package:  tool,
+					Inactive: true,
+				})
+			}
+		}
+	}
+
+	validator := system.NewFileValidator()
+	for _, shim := range validator.ListExecutables(shimsDir) {
+		tool := shimTool(shim, providers[shim])
+
+		// The shim runs the active version's executable of the same name. A
+		// shim only an inactive version provides, such as pyenv's python2,
+		// runs no version until that one is selected.
+		binary := &scanner.Binary{
+			Name:    shim,
+			Path:    filepath.Join(shimsDir, shim),
+			Manager: managerName,
+			Package: tool,
+			Kind:    scanner.KindShim,
+		}
+		if version := activeVersions[tool]; version != "" {
+			binDir, _ := installExecutables(versionDir(tool, version))
+			if validator.IsBinaryExecutable(filepath.Join(binDir, shim)) {
+				binary.Version = version
+				binary.Target = filepath.Join(binDir, shim)
+			} else {
+				binary.Notes = append(binary.Notes, fmt.Sprintf("not provided by the active %s %s", tool, version))
+			}
+		}
+		binaries = append(binaries, binary)
+	}
+
+	return binaries
+}
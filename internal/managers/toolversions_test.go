@@ -0,0 +1,195 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile creates a file and its parent directories for test fixtures
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchInstalledVersion(t *testing.T) {
+	installed := []string{"18.19.0", "20.9.0", "20.11.1", "21.0.0"}
+
+	tests := []struct {
+		request  string
+		expected string
+	}{
+		{"20.9.0", "20.9.0"},
+		{"20", "20.11.1"},
+		{"latest", "21.0.0"},
+		{"19", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.request, func(t *testing.T) {
+			if got := matchInstalledVersion(tt.request, installed); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseMiseTOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mise.toml")
+	writeFile(t, path, `[env]
+NODE_ENV = "development"
+
+[tools]
+node = "20"
+terraform = ["1.5.7", "1.4"] # fallback
+python = { version = "3.11", virtualenv = ".venv" }
+"aqua:kubernetes/kubectl" = "1.29"
+`, 0644)
+
+	requests := parseMiseTOML(path)
+
+	tests := []struct {
+		tool     string
+		expected []string
+	}{
+		{"node", []string{"20"}},
+		{"terraform", []string{"1.5.7", "1.4"}},
+		{"python", []string{"3.11"}},
+		{"aqua-kubernetes-kubectl", []string{"1.29"}},
+	}
+
+	for _, tt := range tests {
+		got := requests[tt.tool]
+		if len(got) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.tool, tt.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.tool, tt.expected, got)
+			}
+		}
+	}
+
+	if _, exists := requests["NODE_ENV"]; exists {
+		t.Error("Expected [env] entries to be ignored")
+	}
+}
+
+func TestFindToolRequestsPrefersClosestFile(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "work", "project")
+	writeFile(t, filepath.Join(root, ".tool-versions"), "nodejs 18.19.0\nterraform 1.4.0\n", 0644)
+	writeFile(t, filepath.Join(project, ".tool-versions"), "nodejs 20.11.1 # pinned\n", 0644)
+
+	requests := findToolRequests(project, func(dir string) toolRequests {
+		return parseToolVersions(filepath.Join(dir, toolVersionsFile))
+	})
+
+	if got := requests["nodejs"]; len(got) != 1 || got[0] != "20.11.1" {
+		t.Errorf("Expected project nodejs version, got %v", got)
+	}
+	if got := requests["terraform"]; len(got) != 1 || got[0] != "1.4.0" {
+		t.Errorf("Expected inherited terraform version, got %v", got)
+	}
+}
+
+func TestAsdfScan(t *testing.T) {
+	dataDir := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("ASDF_NODEJS_VERSION", "")
+
+	writeFile(t, filepath.Join(dataDir, "installs", "nodejs", "18.19.0", "bin", "node"), "", 0755)
+	writeFile(t, filepath.Join(dataDir, "installs", "nodejs", "20.11.1", "bin", "node"), "", 0755)
+	writeFile(t, filepath.Join(dataDir, "shims", "node"), "#!/usr/bin/env bash\n# asdf-plugin: nodejs 20.11.1\n# asdf-plugin: nodejs 18.19.0\nexec asdf exec \"node\" \"$@\"\n", 0755)
+	writeFile(t, filepath.Join(project, ".tool-versions"), "nodejs 20\n", 0644)
+
+	asdf := &Asdf{dataDir: dataDir, workDir: project}
+	if !asdf.IsAvailable(context.Background()) {
+		t.Fatal("Expected asdf to be available")
+	}
+
+	binaries, err := asdf.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(binaries) != 2 {
+		t.Fatalf("Expected 2 binaries, got %d", len(binaries))
+	}
+
+	for _, binary := range binaries {
+		switch binary.Path {
+		case filepath.Join(dataDir, "shims", "node"):
+			if binary.Package != "nodejs" || binary.Version != "20.11.1" || binary.Inactive {
+				t.Errorf("Unexpected shim binary: %s", binary)
+			}
+		case filepath.Join(dataDir, "installs", "nodejs", "18.19.0", "bin", "node"):
+			if !binary.Inactive {
+				t.Errorf("Expected 18.19.0 to be inactive: %s", binary)
+			}
+		default:
+			t.Errorf("Unexpected binary: %s", binary)
+		}
+	}
+}
+
+func TestMiseScan(t *testing.T) {
+	dataDir := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	installs := filepath.Join(dataDir, "installs")
+	writeFile(t, filepath.Join(installs, "python", "3.11.7", "bin", "python"), "", 0755)
+	writeFile(t, filepath.Join(installs, "python", "3.12.1", "bin", "python"), "", 0755)
+	writeFile(t, filepath.Join(installs, "go", "1.22.0", "bin", "go"), "", 0755)
+	writeFile(t, filepath.Join(installs, "go", "1.22.0", "bin", "gofmt"), "", 0755)
+	// The asdf-era golang plugin provides go too
+	writeFile(t, filepath.Join(installs, "golang", "1.21.6", "bin", "go"), "", 0755)
+	for _, shim := range []string{"python", "go", "gofmt"} {
+		writeFile(t, filepath.Join(dataDir, "shims", shim), "\x7fELF", 0755)
+	}
+	writeFile(t, filepath.Join(project, "mise.toml"), "[tools]\npython = \"3.12\"\ngo = \"1.22\"\n", 0644)
+
+	mise := &Mise{dataDir: dataDir, configDir: t.TempDir(), workDir: project}
+	if !mise.IsAvailable(context.Background()) {
+		t.Fatal("Expected mise to be available")
+	}
+
+	expected := map[string]string{
+		filepath.Join(dataDir, "shims", "python"):                    "python 3.12.1 active",
+		filepath.Join(dataDir, "shims", "go"):                        "go 1.22.0 active",
+		filepath.Join(dataDir, "shims", "gofmt"):                     "go 1.22.0 active",
+		filepath.Join(installs, "python", "3.11.7", "bin", "python"): "python 3.11.7 inactive",
+		filepath.Join(installs, "golang", "1.21.6", "bin", "go"):     "golang 1.21.6 inactive",
+	}
+
+	// A shim two tools provide goes to the same tool on every scan
+	for i := 0; i < 5; i++ {
+		binaries, err := mise.Scan(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(binaries) != len(expected) {
+			t.Fatalf("Expected %d binaries, got %d", len(expected), len(binaries))
+		}
+
+		for _, binary := range binaries {
+			state := "active"
+			if binary.Inactive {
+				state = "inactive"
+			}
+			if got := binary.Package + " " + binary.Version + " " + state; got != expected[binary.Path] {
+				t.Errorf("Expected %s to be %q, got %q", binary.Path, expected[binary.Path], got)
+			}
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
+)
+
+// pythonNamePattern matches the separators PEP 503 normalizes in package names
+var pythonNamePattern = regexp.MustCompile(`[-_.]+`)
+
+// UV implements the PackageManager interface for tools installed with
+// `uv tool install` and Python versions installed with `uv python install`
+type UV struct {
+	executor  system.CommandExecutor
+	toolDir   string
+	pythonDir string
+	binDir    string
+}
+
+// NewUV creates a new uv package manager
+func NewUV(executor system.CommandExecutor) *UV {
+	binDir := os.Getenv("UV_TOOL_BIN_DIR")
+	if binDir == "" {
+		binDir = os.Getenv("XDG_BIN_HOME")
+	}
+	if binDir == "" {
+		binDir = filepath.Join(system.GetHomeDir(), ".local", "bin")
+	}
+
+	return &UV{
+		executor: executor,
+		binDir:   binDir,
+	}
+}
+
+// Name returns the name of the package manager
+func (u *UV) Name() string {
+	return "uv"
+}
+
+// IsAvailable checks if uv is installed or has left a tool directory behind
+func (u *UV) IsAvailable(ctx context.Context) bool {
+	if u.executor.IsAvailable(ctx, "uv") {
+		return true
+	}
+	info, err := os.Stat(u.resolveToolDir(ctx))
+	return err == nil && info.IsDir()
+}
+
+// Scan discovers executables exposed by uv tools and uv-managed Pythons
+func (u *UV) Scan(ctx context.Context) ([]*scanner.Binary, error) {
+	var binaries []*scanner.Binary
+
+	binaries = append(binaries, u.scanTools(u.resolveToolDir(ctx))...)
+	binaries = append(binaries, u.scanPythons(u.resolvePythonDir(ctx))...)
+
+	return binaries, nil
+}
+
+// resolveToolDir finds the tool directory from UV_TOOL_DIR, then
+// `uv tool dir`, then the XDG default
+func (u *UV) resolveToolDir(ctx context.Context) string {
+	if u.toolDir == "" {
+		u.toolDir = u.resolveDir(ctx, "UV_TOOL_DIR", "tools", "tool", "dir")
+	}
+	return u.toolDir
+}
+
+// resolvePythonDir finds the managed Python directory from
+// UV_PYTHON_INSTALL_DIR, then `uv python dir`, then the XDG default
+func (u *UV) resolvePythonDir(ctx context.Context) string {
+	if u.pythonDir == "" {
+		u.pythonDir = u.resolveDir(ctx, "UV_PYTHON_INSTALL_DIR", "python", "python", "dir")
+	}
+	return u.pythonDir
+}
+
+// resolveDir looks up one of uv's data directories
+func (u *UV) resolveDir(ctx context.Context, env, name string, args ...string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+
+	if u.executor.IsAvailable(ctx, "uv") {
+		if output, err := u.executor.Execute(ctx, "uv", args...); err == nil {
+			if dir := strings.TrimSpace(output); dir != "" {
+				return dir
+			}
+		}
+	}
+
+	return filepath.Join(xdgDir("UV_DATA_DIR", "XDG_DATA_HOME", filepath.Join(".local", "share"), "uv"), name)
+}
+
+// scanTools reads each tool's uv-receipt.toml for the entrypoints it
+// installed, attributing them to the tool's package and version
+func (u *UV) scanTools(toolDir string) []*scanner.Binary {
+	entries, err := os.ReadDir(toolDir)
+	if err != nil {
+		return nil
+	}
+
+	var binaries []*scanner.Binary
+	validator := system.NewFileValidator()
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		envDir := filepath.Join(toolDir, entry.Name())
+		receipt, err := readTOML(filepath.Join(envDir, "uv-receipt.toml"))
+		if err != nil {
+			continue
+		}
+
+		packageName := entry.Name()
+		if requirements := tomlArray(receipt["tool"]["requirements"]); len(requirements) > 0 {
+			if name := tomlString(tomlInlineTable(requirements[0])["name"]); name != "" {
+				packageName = name
+			}
+		}
+		version := sitePackagesVersion(envDir, packageName)
+
+		for _, item := range tomlArray(receipt["tool"]["entrypoints"]) {
+			entrypoint := tomlInlineTable(item)
+			name := tomlString(entrypoint["name"])
+			if name == "" {
+				continue
+			}
+
+			path := tomlString(entrypoint["install-path"])
+			if path == "" {
+				path = filepath.Join(u.binDir, name)
+			}
+			if !validator.IsBinaryExecutable(path) {
+				continue
+			}
+
+			binaries = append(binaries, &scanner.Binary{
+				Name:    name,
+				Path:    path,
+				Manager: u.Name(),
+				Version: version,
+				Package: packageName,
+			})
+		}
+	}
+
+	return binaries
+}
+
+// scanPythons reports uv-managed Python installations. Executables in the
+// tool bin directory that link into an installation are reported as active;
+// installations without any are reported once, as inactive, with their size.
+func (u *UV) scanPythons(pythonDir string) []*scanner.Binary {
+	entries, err := os.ReadDir(pythonDir)
+	if err != nil {
+		return nil
+	}
+
+	validator := system.NewFileValidator()
+
+	// Resolve every executable in the bin directory once
+	linkNames := validator.ListExecutables(u.binDir)
+	linkTargets := make(map[string]string)
+	for _, name := range linkNames {
+		if target := resolveSymlink(filepath.Join(u.binDir, name)); target != "" {
+			linkTargets[name] = target
+		}
+	}
+
+	var binaries []*scanner.Binary
+	for _, entry := range entries {
+		// Installations are named <implementation>-<version>-<os>-<arch>-<libc>
+		parts := strings.Split(entry.Name(), "-")
+		if !entry.IsDir() || len(parts) < 2 {
+			continue
+		}
+
+		installDir := filepath.Join(pythonDir, entry.Name())
+		resolvedDir, err := filepath.EvalSymlinks(installDir)
+		if err != nil {
+			continue
+		}
+
+		linked := false
+		for _, name := range linkNames {
+			target, ok := linkTargets[name]
+			if !ok || !strings.HasPrefix(target, resolvedDir+string(filepath.Separator)) {
+				continue
+			}
+			linked = true
+
+			binaries = append(binaries, &scanner.Binary{
+				Name:    name,
+				Path:    filepath.Join(u.binDir, name),
+				Manager: u.Name(),
+				Version: parts[1],
+				Package: entry.Name(),
+			})
+		}
+
+		if linked {
+			continue
+		}
+
+		python := filepath.Join(installDir, "bin", "python3")
+		if !validator.IsBinaryExecutable(python) {
+			continue
+		}
+
+		binaries = append(binaries, &scanner.Binary{
+			Name:     "python3",
+			Path:     python,
+			Manager:  u.Name(),
+			Version:  parts[1],
+			Package:  entry.Name(),
+			Inactive: true,
+			Size:     system.DirSize(installDir),
+		})
+	}
+
+	return binaries
+}
+
+// sitePackagesVersion finds a package's installed version from its
+// .dist-info directory in a virtual environment
+func sitePackagesVersion(envDir, packageName string) string {
+	matches, _ := filepath.Glob(filepath.Join(envDir, "lib", "python*", "site-packages", "*.dist-info"))
+	want := normalizePythonName(packageName)
+
+	for _, match := range matches {
+		// Directories are named <normalized_name>-<version>.dist-info
+		name, version, ok := strings.Cut(strings.TrimSuffix(filepath.Base(match), ".dist-info"), "-")
+		if ok && normalizePythonName(name) == want {
+			return version
+		}
+	}
+
+	return ""
+}
+
+// normalizePythonName normalizes a Python package name per PEP 503
+func normalizePythonName(name string) string {
+	return strings.ToLower(pythonNamePattern.ReplaceAllString(name, "-"))
+}
+
+// isVirtualenvEntrypoint reports whether a bin directory entry is a symlink
+// into a virtual environment, as uv and pipx install them, rather than a
+// script pip installed directly
+func isVirtualenvEntrypoint(path string) bool {
+	target := resolveSymlink(path)
+	if target == "" {
+		return false
+	}
+
+	envDir := filepath.Dir(filepath.Dir(target))
+	_, err := os.Stat(filepath.Join(envDir, "pyvenv.cfg"))
+	return err == nil
+}
+
+// resolveSymlink returns the fully resolved target of a symlink, or "" if
+// path isn't a symlink or can't be resolved
+func resolveSymlink(path string) string {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return ""
+	}
+
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return ""
+	}
+	return target
+}
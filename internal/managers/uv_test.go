@@ -0,0 +1,88 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUVScanTools(t *testing.T) {
+	toolDir := t.TempDir()
+	binDir := t.TempDir()
+
+	envDir := filepath.Join(toolDir, "ruff")
+	writeFile(t, filepath.Join(envDir, "pyvenv.cfg"), "home = /usr/bin\n", 0644)
+	writeFile(t, filepath.Join(envDir, "bin", "ruff"), "", 0755)
+	writeFile(t, filepath.Join(envDir, "lib", "python3.12", "site-packages", "ruff-0.5.0.dist-info", "METADATA"), "", 0644)
+	writeFile(t, filepath.Join(envDir, "uv-receipt.toml"), `[tool]
+requirements = [{ name = "ruff" }]
+entrypoints = [
+    { name = "ruff", install-path = "`+filepath.Join(binDir, "ruff")+`" }, # linked
+]
+
+[tool.options]
+exclude-newer = "2024-06-01T00:00:00Z"
+`, 0644)
+
+	if err := os.Symlink(filepath.Join(envDir, "bin", "ruff"), filepath.Join(binDir, "ruff")); err != nil {
+		t.Fatal(err)
+	}
+
+	uv := &UV{executor: &fakeExecutor{}, toolDir: toolDir, pythonDir: t.TempDir(), binDir: binDir}
+	binaries, err := uv.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(binaries) != 1 {
+		t.Fatalf("Expected 1 binary, got %d", len(binaries))
+	}
+
+	ruff := binaries[0]
+	if ruff.Name != "ruff" || ruff.Package != "ruff" || ruff.Version != "0.5.0" || ruff.Path != filepath.Join(binDir, "ruff") {
+		t.Errorf("Unexpected binary: %s", ruff)
+	}
+
+	if !isVirtualenvEntrypoint(ruff.Path) {
+		t.Error("Expected uv entrypoint to be recognised as a virtualenv entrypoint")
+	}
+}
+
+func TestUVScanPythons(t *testing.T) {
+	pythonDir := t.TempDir()
+	binDir := t.TempDir()
+
+	linked := filepath.Join(pythonDir, "cpython-3.12.4-linux-x86_64-gnu")
+	writeFile(t, filepath.Join(linked, "bin", "python3.12"), "\x7fELF", 0755)
+	symlink(t, filepath.Join(linked, "bin", "python3.12"), filepath.Join(binDir, "python3.12"))
+
+	unlinked := filepath.Join(pythonDir, "cpython-3.11.9-linux-x86_64-gnu")
+	writeFile(t, filepath.Join(unlinked, "bin", "python3"), "\x7fELF", 0755)
+	writeFile(t, filepath.Join(unlinked, "lib", "python3.11", "os.py"), "import abc\n", 0644)
+
+	// Not a Python installation
+	writeFile(t, filepath.Join(pythonDir, ".lock"), "", 0644)
+
+	uv := &UV{executor: &fakeExecutor{}, toolDir: t.TempDir(), pythonDir: pythonDir, binDir: binDir}
+	binaries, err := uv.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(binaries) != 2 {
+		t.Fatalf("Expected 2 binaries, got %v", binaries)
+	}
+
+	active := findBinary(binaries, "python3.12")
+	if active == nil || active.Path != filepath.Join(binDir, "python3.12") || active.Version != "3.12.4" ||
+		active.Package != "cpython-3.12.4-linux-x86_64-gnu" || active.Inactive || active.Size != 0 {
+		t.Errorf("Expected linked python3.12 to be active, got %+v", active)
+	}
+
+	inactive := findBinary(binaries, "python3")
+	if inactive == nil || inactive.Path != filepath.Join(unlinked, "bin", "python3") || inactive.Version != "3.11.9" ||
+		!inactive.Inactive || inactive.Size == 0 {
+		t.Errorf("Expected unlinked python3 to be inactive with its size, got %+v", inactive)
+	}
+}
@@ -0,0 +1,116 @@
+package managers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// compareVersions compares two version strings segment by segment.
+// Numeric segments are compared numerically and a trailing pre-release
+// segment (e.g. "1.0.0-rc1") sorts before the release it precedes.
+// It returns -1 if a < b, 0 if they are equal and 1 if a > b.
+func compareVersions(a, b string) int {
+	as := splitVersion(a)
+	bs := splitVersion(b)
+
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareSegment(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(as) > len(bs):
+		return extraSegmentOrder(as[len(bs)])
+	case len(as) < len(bs):
+		return -extraSegmentOrder(bs[len(as)])
+	}
+	return 0
+}
+
+// sortVersions sorts versions in ascending order
+func sortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareVersions(versions[i], versions[j]) < 0
+	})
+}
+
+// latestVersion returns the highest version in the list, or "" if it is empty
+func latestVersion(versions []string) string {
+	latest := ""
+	for _, v := range versions {
+		if latest == "" || compareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// splitVersion breaks a version into its dot, dash, plus and underscore
+// separated segments, dropping a leading "v"
+func splitVersion(v string) []string {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == '.' || r == '-' || r == '+' || r == '_'
+	})
+}
+
+// compareSegment compares a single version segment
+func compareSegment(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		// Numbers sort after words, so "1.0.1" > "1.0.rc1"
+		return 1
+	case bErr == nil:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// extraSegmentOrder decides how a version with one more segment than its
+// otherwise equal counterpart compares: "1.0.1" > "1.0" but "1.0-rc1" < "1.0"
+func extraSegmentOrder(segment string) int {
+	if segment != "" && unicode.IsLetter(rune(segment[0])) {
+		return -1
+	}
+	return 1
+}
+
+// matchInstalledVersion picks the installed version satisfying a request.
+// A request may be an exact version, a prefix such as "20" or "3.11", or
+// "latest". Prefix requests resolve to the newest matching version.
+func matchInstalledVersion(request string, installed []string) string {
+	request = strings.TrimSpace(request)
+	if request == "" {
+		return ""
+	}
+
+	if request == "latest" {
+		return latestVersion(installed)
+	}
+
+	var candidates []string
+	for _, v := range installed {
+		if v == request {
+			return v
+		}
+		if strings.HasPrefix(v, request+".") || strings.HasPrefix(v, request+"-") {
+			candidates = append(candidates, v)
+		}
+	}
+
+	return latestVersion(candidates)
+}
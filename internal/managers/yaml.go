@@ -0,0 +1,157 @@
+package managers
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// yamlDocument holds the scalars of a simple YAML document by dotted path,
+// e.g. "spec.version", and the items of its lists of mappings
+type yamlDocument struct {
+	Values map[string]string
+	Lists  map[string][]map[string]string
+}
+
+// yamlFrame is an open mapping key and the indentation it was found at
+type yamlFrame struct {
+	indent int
+	key    string
+}
+
+// readYAML reads the subset of YAML that plugin manifests and receipts
+// use: nested mappings, plain and quoted scalars, block scalars (skipped)
+// and lists of mappings. Keys inside a list item are stored by their own
+// name, however deeply they're nested, which is enough to read fields such
+// as a platform's os or command.
+func readYAML(path string) (*yamlDocument, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc := &yamlDocument{
+		Values: make(map[string]string),
+		Lists:  make(map[string][]map[string]string),
+	}
+
+	var stack []yamlFrame
+	var item map[string]string
+	itemIndent, skipIndent := -1, -1
+
+	lineScanner := bufio.NewScanner(file)
+	for lineScanner.Scan() {
+		raw := stripYAMLComment(lineScanner.Text())
+		line := strings.TrimSpace(raw)
+		if line == "" || line == "---" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+
+		// Skip the body of a block scalar
+		if skipIndent >= 0 && indent > skipIndent {
+			continue
+		}
+		skipIndent = -1
+
+		if rest, ok := strings.CutPrefix(line, "-"); ok && (rest == "" || rest[0] == ' ') {
+			// Lists may be indented at the same level as their key
+			for len(stack) > 0 && stack[len(stack)-1].indent > indent {
+				stack = stack[:len(stack)-1]
+			}
+			item = make(map[string]string)
+			path := yamlPath(stack, "")
+			doc.Lists[path] = append(doc.Lists[path], item)
+			itemIndent = indent
+
+			line = strings.TrimSpace(rest)
+			indent += len(rest) - len(strings.TrimLeft(rest, " ")) + 1
+			if line == "" {
+				continue
+			}
+		} else if item != nil && indent <= itemIndent {
+			item, itemIndent = nil, -1
+		}
+
+		key, value, ok := cutYAMLKey(line)
+		if !ok {
+			continue
+		}
+
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			skipIndent = indent
+			continue
+		}
+
+		if item != nil {
+			if _, exists := item[key]; !exists && value != "" {
+				item[key] = unquoteYAML(value)
+			}
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if value == "" {
+			stack = append(stack, yamlFrame{indent: indent, key: key})
+			continue
+		}
+		doc.Values[yamlPath(stack, key)] = unquoteYAML(value)
+	}
+
+	return doc, lineScanner.Err()
+}
+
+// cutYAMLKey splits a "key: value" line. The colon must be followed by a
+// space or end the line, so scalars such as URLs aren't mistaken for keys.
+func cutYAMLKey(line string) (string, string, bool) {
+	key, value, ok := strings.Cut(line, ": ")
+	if !ok {
+		key, ok = strings.CutSuffix(line, ":")
+	}
+	if !ok {
+		return "", "", false
+	}
+	return unquoteYAML(strings.TrimSpace(key)), strings.TrimSpace(value), true
+}
+
+// unquoteYAML removes the quotes around a scalar
+func unquoteYAML(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// yamlPath joins the open keys and key into a dotted path
+func yamlPath(stack []yamlFrame, key string) string {
+	parts := make([]string, 0, len(stack)+1)
+	for _, frame := range stack {
+		parts = append(parts, frame.key)
+	}
+	if key != "" {
+		parts = append(parts, key)
+	}
+	return strings.Join(parts, ".")
+}
+
+// stripYAMLComment removes a trailing comment, which YAML only recognizes
+// at the start of a line or after whitespace
+func stripYAMLComment(line string) string {
+	inQuote := rune(0)
+	for i, r := range line {
+		switch {
+		case inQuote != 0:
+			if r == inQuote {
+				inQuote = 0
+			}
+		case r == '"' || r == '\'':
+			inQuote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -3,8 +3,10 @@ package output
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/alexcloudstar/snappoint/internal/scanner"
+	"github.com/alexcloudstar/snappoint/pkg/system"
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 )
@@ -65,6 +67,9 @@ func (t *TableFormatter) Format(result *scanner.ScanResult) error {
 		if version == "" || version == "unknown" {
 			version = "-"
 		}
+		if binary.Inactive {
+			version += " (inactive)"
+		}
 
 		if err := table.Append([]string{
 			binary.Name,
@@ -100,7 +105,17 @@ func (t *TableFormatter) printSummary(result *scanner.ScanResult) {
 		for name, bins := range result.Conflicts {
 			fmt.Printf("  • %s: %d versions detected\n", name, len(bins))
 			for _, bin := range bins {
-				fmt.Printf("    - %s (%s)\n", bin.Path, bin.Manager)
+				source := bin.Manager
+				if bin.IsAlternative() {
+					source = "managed alternative, selected " + bin.Source
+				}
+				if bin.Arch != "" {
+					source += ", " + bin.Arch
+				}
+				if bin.Target != "" && !bin.IsAlternative() {
+					source += ", " + bin.Kind + " → " + bin.Target
+				}
+				fmt.Printf("    - %s (%s)\n", bin.Path, source)
 			}
 		}
 		fmt.Println()
@@ -109,7 +124,40 @@ func (t *TableFormatter) printSummary(result *scanner.ScanResult) {
 	if result.GhostCount() > 0 {
 		fmt.Printf("%s Found %d ghost binaries:\n", red("👻"), result.GhostCount())
 		for _, ghost := range result.Ghosts {
+			if ghost.Origin != nil {
+				fmt.Printf("  • %s: Probably %s, %s confidence (%s)\n", ghost.Name, ghost.Origin.Story, ghost.Origin.Confidence, ghost.Path)
+				if ghost.Origin.Command != "" {
+					ran := ""
+					if !ghost.Origin.RunAt.IsZero() {
+						ran = " on " + ghost.Origin.RunAt.Format("2006-01-02")
+					}
+					fmt.Printf("    $ %s%s\n", ghost.Origin.Command, ran)
+				}
+				continue
+			}
 			fmt.Printf("  • %s: No package manager claims this (%s)\n", ghost.Name, ghost.Path)
 		}
+		fmt.Println()
+	}
+
+	var noted []*scanner.Binary
+	for _, binary := range result.Binaries {
+		if len(binary.Notes) > 0 || binary.Source != "" {
+			noted = append(noted, binary)
+		}
+	}
+
+	if len(noted) > 0 {
+		fmt.Printf("%s Notes:\n", cyan("📝"))
+		for _, binary := range noted {
+			notes := strings.Join(binary.Notes, "; ")
+			if binary.Source != "" {
+				notes = strings.TrimSuffix("from "+binary.Source+"; "+notes, "; ")
+			}
+			if binary.Size > 0 {
+				notes += fmt.Sprintf(" [%s]", system.FormatSize(binary.Size))
+			}
+			fmt.Printf("  • %s %s (%s): %s\n", binary.Name, binary.Version, binary.Path, notes)
+		}
 	}
 }
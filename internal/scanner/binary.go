@@ -3,23 +3,58 @@ package scanner
 import (
 	"fmt"
 	"strings"
+	"time"
+)
+
+// Kinds of executable a binary can be
+const (
+	KindNative    = "native"    // Compiled executable
+	KindScript    = "script"    // Interpreted script
+	KindSymlink   = "symlink"   // Link to an executable elsewhere
+	KindShim      = "shim"      // Stub a version manager uses to run the selected version
+	KindWrapper   = "wrapper"   // Script that execs another executable
+	KindDirectory = "directory" // Not an executable but an install, such as a toolchain or stale keg
 )
 
 // Binary represents a binary executable found on the system
 type Binary struct {
 	Name          string
 	Path          string
-	Manager       string // "homebrew", "npm", "pip", "manual"
+	Manager       string // Name() of the manager that installed it; "manual" or "ghost" when none did
 	Version       string
 	Package       string
+	Prefix        string   // Install prefix for managers with several, e.g. /opt/homebrew
+	Arch          string   // CPU architecture of the installation, e.g. arm64
+	Inactive      bool     // Installed but not the version currently selected on PATH
+	Size          int64    // Disk space used by the install in bytes, when measured
+	Notes         []string // Extra findings such as "keg-only" or "stale version"
+	Source        string   // Where the install came from, e.g. a repository URL
+	Origin        *Origin  // Likely origin of a ghost binary, when one is recognized
+	Kind          string   // One of the Kind constants, "" until classified
+	Target        string   // Executable a shim, wrapper or symlink runs, when known
 	ConflictsWith []*Binary
 }
 
+// Origin explains where a binary no package manager claims most likely
+// came from, such as a known curl | sh installer
+type Origin struct {
+	Story      string    // e.g. "installed by the official starship install script"
+	Confidence string    // "high", "medium" or "low"
+	Command    string    // Shell command that likely installed it, from history
+	RunAt      time.Time // When Command was run, if the history records it
+}
+
 // IsGhost returns true if the binary is not managed by any package manager
 func (b *Binary) IsGhost() bool {
 	return b.Manager == "manual" || b.Manager == "ghost"
 }
 
+// IsAlternative returns true if the binary is a link managed by the
+// alternatives system, whose Source is the candidate it selects
+func (b *Binary) IsAlternative() bool {
+	return b.Manager == "alternatives"
+}
+
 // HasConflicts returns true if this binary conflicts with other versions
 func (b *Binary) HasConflicts() bool {
 	return len(b.ConflictsWith) > 0
@@ -33,6 +68,9 @@ func (b *Binary) String() string {
 		sb.WriteString(fmt.Sprintf(" v%s", b.Version))
 	}
 	sb.WriteString(fmt.Sprintf(" [%s]", b.Manager))
+	if b.Inactive {
+		sb.WriteString(" (inactive)")
+	}
 	return sb.String()
 }
 
@@ -64,9 +102,17 @@ func (sr *ScanResult) AddBinary(binary *Binary) {
 // DetectConflicts finds binaries with the same name but different versions or paths
 func (sr *ScanResult) DetectConflicts() {
 	nameMap := make(map[string][]*Binary)
+	sr.Conflicts = make(map[string][]*Binary)
+	for _, binary := range sr.Binaries {
+		binary.ConflictsWith = nil
+	}
 
-	// Group binaries by name
+	// Group binaries by name, ignoring installs that aren't selected on PATH
+	// and directories, which can't be run whatever they're named
 	for _, binary := range sr.Binaries {
+		if binary.Inactive || binary.Kind == KindDirectory {
+			continue
+		}
 		nameMap[binary.Name] = append(nameMap[binary.Name], binary)
 	}
 
@@ -87,6 +133,13 @@ func (sr *ScanResult) DetectConflicts() {
 	}
 }
 
+// Analyze runs each analyzer over the scan result in order
+func (sr *ScanResult) Analyze(analyzers ...Analyzer) {
+	for _, analyzer := range analyzers {
+		analyzer.Analyze(sr)
+	}
+}
+
 // TotalCount returns the total number of binaries found
 func (sr *ScanResult) TotalCount() int {
 	return len(sr.Binaries)
@@ -116,3 +116,53 @@ func TestScanResultGhostCount(t *testing.T) {
 		t.Errorf("Expected 1 ghost binary, got %d", result.GhostCount())
 	}
 }
+
+func TestScanResultDetectConflictsIgnoresInactive(t *testing.T) {
+	result := NewScanResult()
+
+	result.AddBinary(&Binary{
+		Name:    "node",
+		Path:    "/home/user/.asdf/shims/node",
+		Manager: "asdf",
+		Version: "20.0.0",
+	})
+
+	result.AddBinary(&Binary{
+		Name:     "node",
+		Path:     "/home/user/.asdf/installs/nodejs/18.0.0/bin/node",
+		Manager:  "asdf",
+		Version:  "18.0.0",
+		Inactive: true,
+	})
+
+	result.DetectConflicts()
+
+	if result.ConflictCount() != 0 {
+		t.Errorf("Expected no conflicts, got %d", result.ConflictCount())
+	}
+}
+
+func TestScanResultDetectConflictsIgnoresDirectories(t *testing.T) {
+	result := NewScanResult()
+
+	result.AddBinary(&Binary{
+		Name:    "python",
+		Path:    "/usr/bin/python",
+		Manager: "apt",
+		Version: "3.11.2",
+	})
+
+	// A conda environment named after the tool it holds
+	result.AddBinary(&Binary{
+		Name:    "python",
+		Path:    "/home/user/miniconda3/envs/python",
+		Manager: "conda",
+		Kind:    KindDirectory,
+	})
+
+	result.DetectConflicts()
+
+	if result.ConflictCount() != 0 {
+		t.Errorf("Expected no conflicts, got %d", result.ConflictCount())
+	}
+}
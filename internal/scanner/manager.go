@@ -15,3 +15,10 @@ type PackageManager interface {
 	// Scan discovers all binaries managed by this package manager
 	Scan(ctx context.Context) ([]*Binary, error)
 }
+
+// Analyzer inspects a completed scan to annotate binaries with findings that
+// need the results of more than one package manager
+type Analyzer interface {
+	// Analyze annotates binaries in the result in place
+	Analyze(result *ScanResult)
+}
@@ -70,33 +70,41 @@ func (s *Scanner) Scan(ctx context.Context) (*ScanResult, error) {
 	return result, nil
 }
 
-// ScanSingle scans a single package manager
+// ScanSingle scans every package manager with the given name. A manager
+// may have several instances, such as one Homebrew per install prefix.
 func (s *Scanner) ScanSingle(ctx context.Context, managerName string) (*ScanResult, error) {
 	result := NewScanResult()
 
-	var targetManager PackageManager
+	var targetManagers []PackageManager
 	for _, mgr := range s.managers {
 		if mgr.Name() == managerName {
-			targetManager = mgr
-			break
+			targetManagers = append(targetManagers, mgr)
 		}
 	}
 
-	if targetManager == nil {
+	if len(targetManagers) == 0 {
 		return nil, fmt.Errorf("package manager '%s' not found", managerName)
 	}
 
-	if !targetManager.IsAvailable(ctx) {
-		return nil, fmt.Errorf("package manager '%s' is not available on this system", managerName)
-	}
+	available := false
+	for _, targetManager := range targetManagers {
+		if !targetManager.IsAvailable(ctx) {
+			continue
+		}
+		available = true
 
-	binaries, err := targetManager.Scan(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("%s scan failed: %w", targetManager.Name(), err)
+		binaries, err := targetManager.Scan(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s scan failed: %w", targetManager.Name(), err)
+		}
+
+		for _, binary := range binaries {
+			result.AddBinary(binary)
+		}
 	}
 
-	for _, binary := range binaries {
-		result.AddBinary(binary)
+	if !available {
+		return nil, fmt.Errorf("package manager '%s' is not available on this system", managerName)
 	}
 
 	result.DetectConflicts()
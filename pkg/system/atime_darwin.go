@@ -0,0 +1,22 @@
+//go:build darwin
+
+package system
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// LastAccessTime returns when a file was last read
+func LastAccessTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec), nil
+	}
+	return info.ModTime(), nil
+}
@@ -0,0 +1,24 @@
+//go:build linux
+
+package system
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// LastAccessTime returns when a file was last read. Filesystems mounted with
+// relatime only update this about once a day, which is plenty for spotting
+// tools that haven't been used in months.
+func LastAccessTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Atim.Sec, stat.Atim.Nsec), nil
+	}
+	return info.ModTime(), nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package system
+
+import (
+	"os"
+	"time"
+)
+
+// LastAccessTime returns when a file was last modified, as access times
+// aren't available on this platform
+func LastAccessTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
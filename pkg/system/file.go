@@ -1,7 +1,10 @@
 package system
 
 import (
+	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 )
 
 // FileValidator provides methods for validating file system properties
@@ -38,3 +41,54 @@ func (v *FileValidator) IsBinaryExecutable(path string) bool {
 
 	return true
 }
+
+// ListExecutables returns the names of executable files in a directory.
+// Symlinks are followed, so broken links and links to directories are skipped.
+func (v *FileValidator) ListExecutables(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if v.IsBinaryExecutable(filepath.Join(dir, entry.Name())) {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names
+}
+
+// DirSize returns the total size in bytes of the regular files under a
+// directory. Symlinks are not followed, and unreadable entries are skipped.
+func DirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// FormatSize renders a byte count in human-readable units (e.g. "1.5 GB")
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
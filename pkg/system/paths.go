@@ -54,3 +54,30 @@ func GetCommonBinaryPaths() []string {
 
 	return paths
 }
+
+// IsInPATH reports whether a directory is listed in the PATH environment variable
+func IsInPATH(dir string) bool {
+	dir = filepath.Clean(dir)
+	for _, p := range GetPATH() {
+		if p != "" && filepath.Clean(p) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// LookPath returns the executable a command name resolves to on PATH, or ""
+// when no PATH directory has one
+func LookPath(name string) string {
+	validator := NewFileValidator()
+	for _, dir := range GetPATH() {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if validator.IsBinaryExecutable(path) {
+			return path
+		}
+	}
+	return ""
+}